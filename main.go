@@ -16,17 +16,32 @@ package main
 
 import (
 	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"html/template"
+	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
+	_ "time/tzdata" // the scratch container image ships no zoneinfo for --device-timezone
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/alecthomas/units"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	versioncollector "github.com/prometheus/client_golang/prometheus/collectors/version"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
 	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/buildinfo"
 	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/collector"
 	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/ltosapi"
@@ -35,15 +50,41 @@ import (
 // Config holds the exporter configuration
 type Config struct {
 	ListenAddress   string
+	ListenSocket    string
+	TLSCertFile     string
+	TLSKeyFile      string
+	WebAuthUser     string
+	WebAuthPass     string
 	MetricsPath     string
 	Target          string
 	LogLevel        slog.Level
 	AuthBasicUser   string
 	AuthBasicPass   string
 	IgnoreSSLVerify bool
+	MaxResponseSize units.Base2Bytes
+	UserAgent       string
+	ProxyURL        string
+	MaxRetries      int
+	MaxConcurrency  int
+	FollowRedirects bool
+	MaxIdleConns    int
+	IdleConnTimeout time.Duration
+	Once            bool
+	CheckConfig     bool
+	ScrapeTimeout   time.Duration
+	RequestTimeout  time.Duration
+	TimeoutOffset   time.Duration
+	ExternalLabels  map[string]string
+	Tracing         TracingConfig
 	Collector       collector.Config
 }
 
+// TracingConfig holds the configuration of the optional OpenTelemetry tracing of requests to the device
+type TracingConfig struct {
+	Enabled      bool
+	OTLPEndpoint string
+}
+
 // parseFlags parses command-line flags using kingpin
 func parseFlags() *Config {
 	app := kingpin.New("meinberg_ltos_exporter", "Prometheus exporter for Meinberg LTOS devices")
@@ -59,6 +100,26 @@ func parseFlags() *Config {
 		Envar(envPrefix + "LISTEN_ADDRESS").
 		StringVar(&cfg.ListenAddress)
 
+	app.Flag("web.listen-socket", "Path of a Unix domain socket to serve the web interface and telemetry on, in addition to the listen address (set --web.listen-address to an empty string to serve on the socket only)").
+		Envar(envPrefix + "LISTEN_SOCKET").
+		StringVar(&cfg.ListenSocket)
+
+	app.Flag("web.tls-cert", "Path to a TLS certificate to serve the web interface and telemetry over HTTPS (reloaded on SIGHUP, requires --web.tls-key)").
+		Envar(envPrefix + "TLS_CERT").
+		StringVar(&cfg.TLSCertFile)
+
+	app.Flag("web.tls-key", "Path to the private key of the TLS certificate (requires --web.tls-cert)").
+		Envar(envPrefix + "TLS_KEY").
+		StringVar(&cfg.TLSKeyFile)
+
+	app.Flag("web.auth-user", "Basic auth username required to access the web interface and telemetry (requires --web.auth-pass)").
+		Envar(envPrefix + "WEB_AUTH_USER").
+		StringVar(&cfg.WebAuthUser)
+
+	app.Flag("web.auth-pass", "Basic auth password required to access the web interface and telemetry (prefer env var over CLI flag)").
+		Envar(envPrefix + "WEB_AUTH_PASS").
+		StringVar(&cfg.WebAuthPass)
+
 	app.Flag("web.telemetry-path", "Path under which to expose metrics").
 		Default("/metrics").
 		Envar(envPrefix + "METRICS_PATH").
@@ -82,11 +143,108 @@ func parseFlags() *Config {
 		Envar(envPrefix + "TIMEOUT").
 		DurationVar(&cfg.Collector.Timeout)
 
+	app.Flag("scrape-timeout", "Deadline of a collection when the request carries no X-Prometheus-Scrape-Timeout-Seconds header, e.g. with --once (0 means --timeout)").
+		Default("0s").
+		Envar(envPrefix + "SCRAPE_TIMEOUT").
+		DurationVar(&cfg.ScrapeTimeout)
+
+	app.Flag("request-timeout", "Timeout for each request to the Meinberg device within a collection, e.g. for /api/config next to /api/status, including retries (0 means only the collection deadline applies)").
+		Default("0s").
+		Envar(envPrefix + "REQUEST_TIMEOUT").
+		DurationVar(&cfg.RequestTimeout)
+
+	app.Flag("timeout-offset", "Offset to subtract from the Prometheus scrape timeout (X-Prometheus-Scrape-Timeout-Seconds) to leave room for the response").
+		Default("0.5s").
+		Envar(envPrefix + "TIMEOUT_OFFSET").
+		DurationVar(&cfg.TimeoutOffset)
+
 	app.Flag("ignore-ssl-verify", "Ignore SSL certificate verification").
 		Default("false").
 		Envar(envPrefix + "IGNORE_SSL_VERIFY").
 		BoolVar(&cfg.IgnoreSSLVerify)
 
+	app.Flag("max-response-bytes", "Maximum size of a response body from the Meinberg device (0 disables the limit)").
+		Default("16MiB").
+		Envar(envPrefix + "MAX_RESPONSE_BYTES").
+		BytesVar(&cfg.MaxResponseSize)
+
+	app.Flag("user-agent", "User-Agent header sent with requests to the Meinberg device").
+		Default("meinberg_ltos_exporter/" + buildinfo.Version).
+		Envar(envPrefix + "USER_AGENT").
+		StringVar(&cfg.UserAgent)
+
+	app.Flag("proxy-url", "Proxy URL for requests to the Meinberg device (defaults to HTTP_PROXY, HTTPS_PROXY and NO_PROXY)").
+		Envar(envPrefix + "PROXY_URL").
+		StringVar(&cfg.ProxyURL)
+
+	app.Flag("max-retries", "Maximum number of retries when the Meinberg device answers with 429 or 503 and a Retry-After header, or with an empty body").
+		Default("0").
+		Envar(envPrefix + "MAX_RETRIES").
+		IntVar(&cfg.MaxRetries)
+
+	app.Flag("per-target-concurrency", "Maximum number of concurrent requests to the Meinberg LTOS device (0 means no limit)").
+		Default("1").
+		Envar(envPrefix + "PER_TARGET_CONCURRENCY").
+		IntVar(&cfg.MaxConcurrency)
+
+	app.Flag("follow-redirects", "Follow HTTP redirects from the Meinberg LTOS device, otherwise a redirect (e.g. to a login page) fails the scrape").
+		Default("true").
+		Envar(envPrefix + "FOLLOW_REDIRECTS").
+		BoolVar(&cfg.FollowRedirects)
+
+	app.Flag("circuit-breaker.threshold", "Number of consecutive failed scrapes after which requests to the Meinberg LTOS device are paused for the cooldown, e.g. while it reboots (0 disables the circuit breaker)").
+		Default("0").
+		Envar(envPrefix + "CIRCUIT_BREAKER_THRESHOLD").
+		IntVar(&cfg.Collector.BreakerThreshold)
+
+	app.Flag("circuit-breaker.cooldown", "How long requests to the Meinberg LTOS device are paused once the circuit breaker opened").
+		Default("1m").
+		Envar(envPrefix + "CIRCUIT_BREAKER_COOLDOWN").
+		DurationVar(&cfg.Collector.BreakerCooldown)
+
+	app.Flag("max-idle-conns", "Maximum number of idle keep-alive connections to the Meinberg device (0 means no limit)").
+		Default("1").
+		Envar(envPrefix + "MAX_IDLE_CONNS").
+		IntVar(&cfg.MaxIdleConns)
+
+	app.Flag("idle-conn-timeout", "How long an idle keep-alive connection to the Meinberg device is kept open (0 means no limit)").
+		Default("90s").
+		Envar(envPrefix + "IDLE_CONN_TIMEOUT").
+		DurationVar(&cfg.IdleConnTimeout)
+
+	app.Flag("host-label", "Source of the host label on device metrics (device: hostname reported by the device, target: host of the target URL)").
+		Default(collector.HostLabelDevice).
+		Envar(envPrefix+"HOST_LABEL").
+		EnumVar(&cfg.Collector.HostLabel, collector.HostLabelDevice, collector.HostLabelTarget)
+
+	app.Flag("on-error", "Device metrics emitted when a scrape fails (drop: none, stale: last known values with stale 1, nan: last known series with NaN values)").
+		Default(collector.OnErrorDrop).
+		Envar(envPrefix+"ON_ERROR").
+		EnumVar(&cfg.Collector.OnError, collector.OnErrorDrop, collector.OnErrorStale, collector.OnErrorNaN)
+
+	deviceTimezoneFlag := app.Flag("device-timezone", "Timezone of the Meinberg device used to interpret event timestamps (e.g. UTC, Europe/Zurich)").
+		Default("UTC").
+		Envar(envPrefix + "DEVICE_TIMEZONE").
+		String()
+
+	app.Flag("enable-tracing", "Export OpenTelemetry traces of the requests to the Meinberg LTOS device via OTLP/HTTP (requires a build with -tags otel)").
+		Default("false").
+		Envar(envPrefix + "ENABLE_TRACING").
+		BoolVar(&cfg.Tracing.Enabled)
+
+	app.Flag("otlp-endpoint", "OTLP/HTTP endpoint URL for traces (defaults to OTEL_EXPORTER_OTLP_ENDPOINT or http://localhost:4318)").
+		Envar(envPrefix + "OTLP_ENDPOINT").
+		StringVar(&cfg.Tracing.OTLPEndpoint)
+
+	externalLabelsFlag := app.Flag("external-labels", "Static label added to all metrics as key=value, e.g. site=zurich (repeatable)").
+		Envar(envPrefix + "EXTERNAL_LABELS").
+		StringMap()
+
+	app.Flag("once", "Collect metrics once, print them to stdout and exit without starting the web server").
+		Default("false").
+		Envar(envPrefix + "ONCE").
+		BoolVar(&cfg.Once)
+
 	logLevelFlag := app.Flag("log-level", "Log level (debug, info, warn, error)").
 		Default("info").
 		Enum("debug", "info", "warn", "error")
@@ -106,6 +264,11 @@ func parseFlags() *Config {
 		Envar(envPrefix + "COLLECTOR_NETWORK").
 		BoolVar(&cfg.Collector.Network)
 
+	app.Flag("collector.network.port-details", "Enable per-port network metrics (link, info and statistics).").
+		Default("true").
+		Envar(envPrefix + "COLLECTOR_NETWORK_PORT_DETAILS").
+		BoolVar(&cfg.Collector.NetworkPortDetails)
+
 	app.Flag("collector.storage", "Enable storage collector.").
 		Default("true").
 		Envar(envPrefix + "COLLECTOR_STORAGE").
@@ -121,12 +284,31 @@ func parseFlags() *Config {
 		Envar(envPrefix + "COLLECTOR_RECEIVER").
 		BoolVar(&cfg.Collector.Receiver)
 
+	app.Flag("collector.receiver.gnss-position", "Enable GNSS receiver position metrics (latitude, longitude and altitude), disable if the device location is sensitive.").
+		Default("true").
+		Envar(envPrefix + "COLLECTOR_RECEIVER_GNSS_POSITION").
+		BoolVar(&cfg.Collector.GNSSPosition)
+
 	app.Flag("collector.ntp", "Enable NTP collector.").
 		Default("true").
 		Envar(envPrefix + "COLLECTOR_NTP").
 		BoolVar(&cfg.Collector.NTP)
 
-	kingpin.MustParse(app.Parse(os.Args[1:]))
+	app.Flag("collector.config", "Enable config collector, which fetches the configured settings with an additional request per scrape.").
+		Default("false").
+		Envar(envPrefix + "COLLECTOR_CONFIG").
+		BoolVar(&cfg.Collector.DeviceConfig)
+
+	app.Command("serve", "Serve metrics of the Meinberg LTOS device (default)").Default()
+	checkConfigCmd := app.Command("check-config", "Validate the configuration and exit with a non-zero status if it has problems")
+
+	cfg.CheckConfig = kingpin.MustParse(app.Parse(os.Args[1:])) == checkConfigCmd.FullCommand()
+
+	cfg.ExternalLabels = *externalLabelsFlag
+
+	deviceTimezone, err := time.LoadLocation(*deviceTimezoneFlag)
+	app.FatalIfError(err, "invalid --device-timezone")
+	cfg.Collector.DeviceTimezone = deviceTimezone
 
 	if err := cfg.LogLevel.UnmarshalText([]byte(*logLevelFlag)); err != nil {
 		cfg.LogLevel = slog.LevelInfo
@@ -135,12 +317,96 @@ func parseFlags() *Config {
 	return cfg
 }
 
+// Validate checks the configuration for problems that would prevent the exporter from starting, reporting all
+// of them at once
+func (c *Config) Validate() error {
+	var errs []error
+
+	if !c.Once && c.ListenAddress == "" && c.ListenSocket == "" {
+		errs = append(errs, errors.New("no listen address or socket configured"))
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, errors.New("--web.tls-cert and --web.tls-key must be set together"))
+	} else if c.TLSCertFile != "" {
+		if _, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile); err != nil {
+			errs = append(errs, fmt.Errorf("failed to load TLS certificate: %w", err))
+		}
+	}
+
+	if c.ScrapeTimeout < 0 {
+		errs = append(errs, errors.New("--scrape-timeout must not be negative"))
+	}
+	if c.RequestTimeout < 0 {
+		errs = append(errs, errors.New("--request-timeout must not be negative"))
+	}
+
+	if (c.WebAuthUser == "") != (c.WebAuthPass == "") {
+		errs = append(errs, errors.New("--web.auth-user and --web.auth-pass must be set together"))
+	}
+
+	labelsOK := true
+	for name := range c.ExternalLabels {
+		if !model.LegacyValidation.IsValidLabelName(name) || strings.HasPrefix(name, model.ReservedLabelPrefix) {
+			errs = append(errs, fmt.Errorf("invalid external label name %q", name))
+			labelsOK = false
+		}
+	}
+
+	client, err := c.newClient(nil)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to create LTOS API client: %w", err))
+	} else if labelsOK {
+		// Registering the collector checks the external labels against the labels of its metrics
+		reg := prometheus.WrapRegistererWith(c.ExternalLabels, prometheus.NewRegistry())
+		if err := reg.Register(collector.NewCollector(c.Collector, client, slog.New(slog.DiscardHandler))); err != nil {
+			errs = append(errs, fmt.Errorf("invalid external labels: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// newClient creates the LTOS API client for the configured target, with its transport wrapped by wrapTransport
+// unless nil
+func (c *Config) newClient(wrapTransport func(http.RoundTripper) http.RoundTripper) (*ltosapi.Client, error) {
+	return ltosapi.NewClient(c.Target,
+		ltosapi.WithBasicAuth(c.AuthBasicUser, c.AuthBasicPass),
+		ltosapi.WithInsecureSkipVerify(c.IgnoreSSLVerify),
+		ltosapi.WithTimeout(c.RequestTimeout),
+		ltosapi.WithMaxResponseBytes(int64(c.MaxResponseSize)),
+		ltosapi.WithUserAgent(c.UserAgent),
+		ltosapi.WithProxyURL(c.ProxyURL),
+		ltosapi.WithMaxRetries(c.MaxRetries),
+		ltosapi.WithFollowRedirects(c.FollowRedirects),
+		ltosapi.WithMaxConcurrentFetches(c.MaxConcurrency),
+		ltosapi.WithMaxIdleConns(c.MaxIdleConns),
+		ltosapi.WithIdleConnTimeout(c.IdleConnTimeout),
+		ltosapi.WithTransportWrapper(wrapTransport),
+	)
+}
+
 func main() {
 	cfg := parseFlags()
 
+	if cfg.CheckConfig {
+		if err := cfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "FAILED: configuration is invalid:\n%v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("SUCCESS: configuration is valid")
+		return
+	}
+
 	logLevel := &slog.LevelVar{}
 	logLevel.Set(cfg.LogLevel)
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel}))
+
+	// In one-shot mode stdout carries the metrics, so logs go to stderr
+	logOutput := os.Stdout
+	if cfg.Once {
+		logOutput = os.Stderr
+	}
+	logger := slog.New(slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: logLevel}))
 
 	logger.Info("Starting Meinberg LTOS Exporter",
 		"version", buildinfo.Version,
@@ -148,21 +414,78 @@ func main() {
 		"target", cfg.Target,
 	)
 
+	if err := cfg.Validate(); err != nil {
+		logger.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
 	if cfg.IgnoreSSLVerify {
 		logger.Warn("TLS certificate verification disabled via --ignore-ssl-verify")
 	}
 
-	client, err := ltosapi.NewClient(cfg.Target, cfg.AuthBasicUser, cfg.AuthBasicPass, cfg.IgnoreSSLVerify)
+	wrapTransport, shutdownTracing, err := setupTracing(context.Background(), cfg.Tracing, logger)
+	if err != nil {
+		logger.Error("failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Warn("Failed to flush traces", "error", err)
+		}
+	}()
+
+	client, err := cfg.newClient(wrapTransport)
 	if err != nil {
 		logger.Error("failed to create LTOS API client", "error", err)
 		os.Exit(1)
 	}
 
-	prometheus.MustRegister(collector.NewCollector(cfg.Collector, client, logger))
-	prometheus.MustRegister(versioncollector.NewCollector(prometheus.BuildFQName(collector.MetricNamespace, "", "exporter")))
+	ltosCollector := collector.NewCollector(cfg.Collector, client, logger)
+
+	if cfg.Once {
+		timeout := scrapeTimeout("", cfg.Collector.Timeout, cfg.ScrapeTimeout, cfg.TimeoutOffset, logger)
+		if err := collectOnce(ltosCollector.WithTimeout(timeout), cfg.ExternalLabels, os.Stdout); err != nil {
+			logger.Error("failed to collect metrics", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// A registry of its own instead of the default one, whose Go and process collectors lack the external labels
+	registry := prometheus.NewRegistry()
+	registerer := prometheus.WrapRegistererWith(cfg.ExternalLabels, registry)
+	registerer.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	registerer.MustRegister(versioncollector.NewCollector(prometheus.BuildFQName(collector.MetricNamespace, "", "exporter")))
+	registerer.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: collector.MetricNamespace,
+		Subsystem: "exporter",
+		Name:      "config_info",
+		Help:      "Configuration of this exporter instance as labels (target, timeout, TLS verification, host label source)",
+		ConstLabels: prometheus.Labels{
+			"target":          client.Target(),
+			"timeout_seconds": strconv.FormatFloat(cfg.Collector.Timeout.Seconds(), 'f', -1, 64),
+			"tls_verify":      strconv.FormatBool(!cfg.IgnoreSSLVerify),
+			"host_label":      cfg.Collector.HostLabel,
+		},
+	}, func() float64 { return 1 }))
+
+	startTime := float64(time.Now().Unix())
+	registerer.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: collector.MetricNamespace,
+		Subsystem: "exporter",
+		Name:      "start_time_seconds",
+		Help:      "Start time of this exporter instance since unix epoch in seconds",
+	}, func() float64 { return startTime }))
 
 	mux := http.NewServeMux()
-	mux.Handle(cfg.MetricsPath, promhttp.Handler())
+	mux.Handle(cfg.MetricsPath, promhttp.InstrumentMetricHandler(
+		registerer,
+		metricsHandler(registry, ltosCollector, cfg.Collector.Timeout, cfg.ScrapeTimeout, cfg.TimeoutOffset, cfg.ExternalLabels, logger),
+	))
 
 	landingPageData := struct {
 		Target      string
@@ -197,7 +520,12 @@ func main() {
 		})
 	}
 
-	srv := &http.Server{Addr: cfg.ListenAddress, Handler: mux}
+	var handler http.Handler = mux
+	if cfg.WebAuthUser != "" {
+		handler = basicAuth(mux, cfg.WebAuthUser, cfg.WebAuthPass)
+	}
+
+	srv := &http.Server{Addr: cfg.ListenAddress, Handler: handler}
 
 	go func() {
 		sigCh := make(chan os.Signal, 1)
@@ -209,10 +537,183 @@ func main() {
 		}
 	}()
 
-	logger.Info("HTTP server listening", "address", cfg.ListenAddress)
+	serve := srv.Serve
+	listenAndServe := srv.ListenAndServe
+	if cfg.TLSCertFile != "" {
+		certs, err := newCertReloader(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			logger.Error("failed to load TLS certificate", "cert", cfg.TLSCertFile, "key", cfg.TLSKeyFile, "error", err)
+			os.Exit(1)
+		}
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: certs.getCertificate}
+		serve = func(l net.Listener) error { return srv.ServeTLS(l, "", "") }
+		listenAndServe = func() error { return srv.ListenAndServeTLS("", "") }
+
+		go func() {
+			hupCh := make(chan os.Signal, 1)
+			signal.Notify(hupCh, syscall.SIGHUP)
+			for range hupCh {
+				if err := certs.reload(); err != nil {
+					logger.Error("failed to reload TLS certificate, keeping the previous one", "error", err)
+					continue
+				}
+				logger.Info("Reloaded TLS certificate", "cert", cfg.TLSCertFile)
+			}
+		}()
+	}
+
+	serveErrs := make(chan error, 2)
 
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if cfg.ListenSocket != "" {
+		listener, err := listenUnixSocket(cfg.ListenSocket)
+		if err != nil {
+			logger.Error("failed to listen on unix socket", "path", cfg.ListenSocket, "error", err)
+			os.Exit(1)
+		}
+		logger.Info("HTTP server listening", "socket", cfg.ListenSocket, "tls", srv.TLSConfig != nil)
+		// Closing the listener on shutdown removes the socket file
+		go func() { serveErrs <- serve(listener) }()
+	}
+
+	if cfg.ListenAddress != "" {
+		logger.Info("HTTP server listening", "address", cfg.ListenAddress, "tls", srv.TLSConfig != nil)
+		go func() { serveErrs <- listenAndServe() }()
+	}
+
+	if err := <-serveErrs; err != nil && err != http.ErrServerClosed {
 		logger.Error("HTTP server error", "error", err)
 		os.Exit(1)
 	}
 }
+
+// listenUnixSocket listens on the unix socket at path, removing a stale socket left behind by a previous run
+func listenUnixSocket(path string) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	return net.Listen("unix", path)
+}
+
+// certReloader holds the TLS certificate served by the web server and swaps it on reload, so a renewed
+// certificate can be picked up without restarting the exporter
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// newCertReloader loads the certificate and key pair, failing if either cannot be read
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// reload reads the certificate and key pair from disk again, keeping the current one on error
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// basicAuth rejects requests to next that do not carry the configured basic auth credentials
+func basicAuth(next http.Handler, username, password string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		userOK := subtle.ConstantTimeCompare([]byte(user), []byte(username)) == 1
+		passOK := subtle.ConstantTimeCompare([]byte(pass), []byte(password)) == 1
+		if !ok || !userOK || !passOK {
+			w.Header().Set("WWW-Authenticate", `Basic realm="meinberg-ltos-exporter"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// metricsHandler serves the exporter's own metrics from gatherer together with the LTOS collector carrying the
+// external labels, whose timeout is derived from the Prometheus scrape timeout by scrapeTimeout
+func metricsHandler(gatherer prometheus.Gatherer, c *collector.Collector, timeout, fallback, offset time.Duration, externalLabels prometheus.Labels, logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		effectiveTimeout := scrapeTimeout(r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"), timeout, fallback, offset, logger)
+
+		reg := prometheus.NewRegistry()
+		prometheus.WrapRegistererWith(externalLabels, reg).MustRegister(c.WithTimeout(effectiveTimeout))
+
+		promhttp.HandlerFor(prometheus.Gatherers{gatherer, reg}, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	})
+}
+
+// scrapeTimeout returns the deadline of a collection, never above timeout. It is the Prometheus scrape timeout from
+// header minus offset, or the whole scrape timeout if it is not larger than offset. Without a valid header, fallback
+// applies unless it is 0.
+func scrapeTimeout(header string, timeout, fallback, offset time.Duration, logger *slog.Logger) time.Duration {
+	effective := timeout
+	if fallback > 0 {
+		effective = fallback
+	}
+
+	if header != "" {
+		seconds, err := strconv.ParseFloat(header, 64)
+		if err == nil && !(seconds > 0) {
+			err = errors.New("not a positive number")
+		}
+		if err != nil {
+			logger.Warn("Failed to parse scrape timeout header", "value", header, "error", err)
+		} else {
+			// Clamped before the conversion, which would overflow for huge values
+			seconds = min(seconds, (timeout + offset).Seconds())
+			effective = time.Duration(seconds * float64(time.Second))
+			if effective > offset {
+				effective -= offset
+			}
+		}
+	}
+
+	return min(effective, timeout)
+}
+
+// collectOnce gathers the metrics of a single collection with the external labels and writes them to w in text
+// exposition format
+func collectOnce(c prometheus.Collector, externalLabels prometheus.Labels, w io.Writer) error {
+	reg := prometheus.NewRegistry()
+	if err := prometheus.WrapRegistererWith(externalLabels, reg).Register(c); err != nil {
+		return fmt.Errorf("failed to register collector: %w", err)
+	}
+
+	metricFamilies, err := reg.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	enc := expfmt.NewEncoder(w, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, mf := range metricFamilies {
+		if err := enc.Encode(mf); err != nil {
+			return fmt.Errorf("failed to encode metric family %s: %w", mf.GetName(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestScrapeTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		fallback time.Duration
+		want     time.Duration
+	}{
+		{name: "header minus offset", header: "3", want: 2500 * time.Millisecond},
+		{name: "header above timeout", header: "30", want: 5 * time.Second},
+		{name: "huge header", header: "1e300", want: 5 * time.Second},
+		{name: "header smaller than offset", header: "0.25", want: 250 * time.Millisecond},
+		{name: "header overrides fallback", header: "3", fallback: time.Second, want: 2500 * time.Millisecond},
+		{name: "no header", want: 5 * time.Second},
+		{name: "no header with fallback", fallback: 2 * time.Second, want: 2 * time.Second},
+		{name: "fallback above timeout", fallback: 10 * time.Second, want: 5 * time.Second},
+		{name: "unparseable header", header: "soon", want: 5 * time.Second},
+		{name: "unparseable header with fallback", header: "soon", fallback: 2 * time.Second, want: 2 * time.Second},
+		{name: "negative header", header: "-1", want: 5 * time.Second},
+		{name: "NaN header", header: "NaN", want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scrapeTimeout(tt.header, 5*time.Second, tt.fallback, 500*time.Millisecond, slog.New(slog.DiscardHandler))
+			if got != tt.want {
+				t.Errorf("scrapeTimeout(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package collector
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker stops fetching from a device that failed threshold consecutive fetches for the cooldown, so a
+// rebooting device is not hammered with requests (and retries) and scrapes fail fast in the meantime. After the
+// cooldown a single fetch is let through again, which either closes the circuit or opens it for another cooldown.
+type circuitBreaker struct {
+	threshold int // 0 disables the breaker
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+func (b *circuitBreaker) enabled() bool {
+	return b.threshold > 0
+}
+
+// isOpen reports whether fetches are currently skipped
+func (b *circuitBreaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.now().Before(b.openUntil)
+}
+
+// record counts the outcome of a fetch and reports whether it opened the circuit
+func (b *circuitBreaker) record(err error) bool {
+	if !b.enabled() {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.failures = 0
+		return false
+	}
+
+	b.failures++
+	if b.failures < b.threshold {
+		return false
+	}
+	b.openUntil = b.now().Add(b.cooldown)
+	return true
+}
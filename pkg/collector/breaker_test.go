@@ -0,0 +1,51 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	errFetch := errors.New("connection refused")
+
+	if b.record(errFetch) || b.isOpen() {
+		t.Fatal("expected circuit to stay closed below the threshold")
+	}
+	if b.record(nil) || b.isOpen() {
+		t.Fatal("expected success to keep the circuit closed")
+	}
+	if b.record(errFetch) {
+		t.Fatal("expected success to reset the consecutive failures")
+	}
+	if !b.record(errFetch) || !b.isOpen() {
+		t.Fatal("expected circuit to open at the threshold")
+	}
+
+	now = now.Add(time.Minute)
+	if b.isOpen() {
+		t.Fatal("expected circuit to let a fetch through after the cooldown")
+	}
+	if !b.record(errFetch) || !b.isOpen() {
+		t.Fatal("expected a failure after the cooldown to open the circuit again")
+	}
+
+	now = now.Add(time.Minute)
+	b.record(nil)
+	if b.record(errFetch) || b.isOpen() {
+		t.Fatal("expected success after the cooldown to close the circuit")
+	}
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	b := newCircuitBreaker(0, time.Minute)
+	for range 10 {
+		if b.record(errors.New("connection refused")) || b.isOpen() {
+			t.Fatal("expected a disabled circuit breaker to never open")
+		}
+	}
+}
@@ -12,7 +12,7 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, clockSubsystem, "info"),
 			"Meinberg clock module information as labels (model, serial number, software revision, oscillator type)",
-			[]string{"host", "clock_id", "model", "serial_number", "software_revision", "oscillator_type"},
+			[]string{"host", "chassis", "clock_id", "model", "serial_number", "software_revision", "oscillator_type"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -21,7 +21,25 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, clockSubsystem, "synchronized"),
 			"Meinberg clock synchronization status (1 = synchronized, 0 = not synchronized)",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	clkReceiversUnsynced = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, "", "receivers_unsynced"),
+			"Number of Meinberg clock modules not synchronized (see clock_synchronized for the affected modules)",
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	clkStateInfo = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, clockSubsystem, "state_info"),
+			"Meinberg clock synchronization state as reported by the device (e.g. synchronized, not-synchronized, holdover)",
+			[]string{"host", "chassis", "clock_id", "state"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -30,7 +48,7 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, clockSubsystem, "oscillator_warmed_up"),
 			"Meinberg clock oscillator warmed up status (1 = warmed up, 0 = not warmed up)",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -39,7 +57,43 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, clockSubsystem, "estimated_time_quality_seconds"),
 			"Estimated upper bound in seconds on the time quality of the clock",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	clkOscillatorTypeInfo = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, clockSubsystem, "oscillator_type_info"),
+			"Meinberg clock module oscillator as labels (type as reported, e.g. ocxo-lq, and class: tcxo, ocxo, rubidium, other or unknown)",
+			[]string{"host", "chassis", "clock_id", "type", "class"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	clkModules = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, clockSubsystem, "modules"),
+			"Number of clock modules present, e.g. to alert when a redundant system drops to a single module",
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	clkPTPClockClass = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, clockSubsystem, "class"),
+			"IEEE 1588 clockClass derived from the clock status (6 = locked, 7 = holdover, 52 = degraded, 248 = default)",
+			[]string{"host", "chassis", "clock_id"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	clkPTPClockAccuracy = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, clockSubsystem, "accuracy"),
+			"IEEE 1588 clockAccuracy enumeration value derived from the estimated time quality (e.g. 33 = 0x21 = within 100ns)",
+			[]string{"host", "chassis", "clock_id"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -49,21 +103,43 @@ var (
 func describeClock(ch chan<- *prometheus.Desc) {
 	ch <- clkInfo.desc
 	ch <- clkSyncStatus.desc
+	ch <- clkReceiversUnsynced.desc
+	ch <- clkModules.desc
+	ch <- clkOscillatorTypeInfo.desc
+	ch <- clkStateInfo.desc
 	ch <- clkOscillatorWarmedUp.desc
 	ch <- clkEstTimeQuality.desc
+	ch <- clkPTPClockClass.desc
+	ch <- clkPTPClockAccuracy.desc
 }
 
 func (c *Collector) collectClock(ch chan<- prometheus.Metric, host string, slots []models.Slot) {
+	modules := 0
+	unsynced := 0
 	forEachClockSlot(slots, func(slot models.Slot) {
+		modules++
 		oscillatorType := "unknown"
 		if slot.Module.SyncStatus != nil {
 			oscillatorType = slot.Module.SyncStatus.OscillatorType
-			ch <- clkSyncStatus.mustNewConstMetric(boolToFloat64(slot.Module.SyncStatus.ClockStatus.IsSynchronized()), host, slot.Name)
-			ch <- clkOscillatorWarmedUp.mustNewConstMetric(boolToFloat64(slot.Module.SyncStatus.ClockStatus.IsOscillatorWarmedUp()), host, slot.Name)
+			ch <- clkSyncStatus.mustNewConstMetric(boolToFloat64(slot.Module.SyncStatus.ClockStatus.IsSynchronized()), host, chassisLabel(slot), slot.Name)
+			if !slot.Module.SyncStatus.ClockStatus.IsSynchronized() {
+				unsynced++
+			}
+			state := slot.Module.SyncStatus.ClockStatus.Clock
+			if state == "" {
+				state = "unknown"
+			}
+			ch <- clkStateInfo.mustNewConstMetric(1.0, host, chassisLabel(slot), slot.Name, state)
+			ch <- clkOscillatorTypeInfo.mustNewConstMetric(1.0, host, chassisLabel(slot), slot.Name, slot.Module.SyncStatus.OscillatorType, slot.Module.SyncStatus.OscillatorClass())
+			ch <- clkOscillatorWarmedUp.mustNewConstMetric(boolToFloat64(slot.Module.SyncStatus.ClockStatus.IsOscillatorWarmedUp()), host, chassisLabel(slot), slot.Name)
+			ch <- clkPTPClockClass.mustNewConstMetric(float64(slot.Module.SyncStatus.ClockStatus.PTPClockClass()), host, chassisLabel(slot), slot.Name)
 			if slot.Module.SyncStatus.TimeQuality != nil {
-				ch <- clkEstTimeQuality.mustNewConstMetric(slot.Module.SyncStatus.TimeQuality.Seconds(), host, slot.Name)
+				ch <- clkEstTimeQuality.mustNewConstMetric(slot.Module.SyncStatus.TimeQuality.Seconds(), host, chassisLabel(slot), slot.Name)
+				ch <- clkPTPClockAccuracy.mustNewConstMetric(float64(slot.Module.SyncStatus.TimeQuality.PTPClockAccuracy()), host, chassisLabel(slot), slot.Name)
 			}
 		}
-		ch <- clkInfo.mustNewConstMetric(1.0, host, slot.Name, slot.Module.Info.Model, slot.Module.Info.SerialNumber.String(), slot.Module.Info.SoftwareRevision, oscillatorType)
+		ch <- clkInfo.mustNewConstMetric(1.0, host, chassisLabel(slot), slot.Name, slot.Module.Info.Model, slot.Module.Info.SerialNumber.String(), slot.Module.Info.SoftwareRevision, oscillatorType)
 	})
+	ch <- clkReceiversUnsynced.mustNewConstMetric(float64(unsynced), host)
+	ch <- clkModules.mustNewConstMetric(float64(modules), host)
 }
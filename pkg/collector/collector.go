@@ -17,12 +17,19 @@ package collector
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"log/slog"
+	"math"
+	"net/http/httptrace"
 	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/ltosapi"
 	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/ltosapi/models"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -30,17 +37,38 @@ const (
 	rootSubsystem   = ""
 )
 
+// Handling of device metrics when a scrape fails
+const (
+	OnErrorDrop  = "drop"  // emit no device metrics
+	OnErrorStale = "stale" // emit the last known values and mark them as stale
+	OnErrorNaN   = "nan"   // emit the last known series with NaN values
+)
+
+// Sources for the value of the host label on device metrics
+const (
+	HostLabelDevice = "device"
+	HostLabelTarget = "target"
+)
+
 var scrapeID atomic.Uint64
 
 type Config struct {
-	Timeout      time.Duration
-	System       bool
-	Notification bool
-	Network      bool
-	Storage      bool
-	Clock        bool
-	Receiver     bool
-	NTP          bool
+	Timeout            time.Duration
+	HostLabel          string
+	OnError            string         // one of OnErrorDrop (default), OnErrorStale or OnErrorNaN
+	DeviceTimezone     *time.Location // Timezone of timestamps reported without zone information, defaults to UTC
+	BreakerThreshold   int            // Consecutive failed fetches after which fetching pauses for BreakerCooldown, 0 disables
+	BreakerCooldown    time.Duration
+	System             bool
+	Notification       bool
+	Network            bool
+	NetworkPortDetails bool
+	Storage            bool
+	Clock              bool
+	Receiver           bool
+	GNSSPosition       bool // emit the GNSS receiver position, which some operators consider sensitive
+	NTP                bool
+	DeviceConfig       bool // also fetch the configured settings, if the client implements FetchConfig
 }
 
 type StatusFetcher interface {
@@ -48,6 +76,26 @@ type StatusFetcher interface {
 	Target() string
 }
 
+// retryCounter is implemented by clients that retry requests the device asked to retry later or answered empty
+type retryCounter interface {
+	Retries() uint64
+}
+
+// statusCodeReporter is implemented by clients that keep the HTTP status code of the last fetch
+type statusCodeReporter interface {
+	LastStatusCode() int
+}
+
+// configFetcher is implemented by clients that fetch the device configuration
+type configFetcher interface {
+	FetchConfig(ctx context.Context, logger *slog.Logger) (*models.ConfigResponse, error)
+}
+
+// responseSizeReporter is implemented by clients that keep the body size of the last fetch
+type responseSizeReporter interface {
+	LastResponseBytes() int64
+}
+
 type Collector struct {
 	config Config
 	client StatusFetcher
@@ -56,6 +104,27 @@ type Collector struct {
 	up             typedDesc
 	scrapeDuration typedDesc
 	buildInfo      typedDesc
+	firmware       typedDesc
+	apiSupported   typedDesc
+	fetchRetries   typedDesc
+	authOK         typedDesc
+	lastError      typedDesc
+	stale          typedDesc
+	fetchDNS       typedDesc
+	fetchConnect   typedDesc
+	fetchTLS       typedDesc
+	circuitOpen    typedDesc
+	httpStatusCode typedDesc
+	responseBytes  typedDesc
+
+	fetchDuration prometheus.Histogram
+	parseErrors   *prometheus.CounterVec
+
+	// fetches deduplicates concurrent scrapes, e.g. from an HA pair of Prometheus servers, into one request
+	fetches *singleflight.Group
+	// lastStatus is the status of the last successful fetch, replayed on failure unless OnError is OnErrorDrop
+	lastStatus *atomic.Pointer[models.StatusResponse]
+	breaker    *circuitBreaker
 }
 
 func NewCollector(config Config, client StatusFetcher, logger *slog.Logger) *Collector {
@@ -80,11 +149,17 @@ func NewCollector(config Config, client StatusFetcher, logger *slog.Logger) *Col
 	if !config.NTP {
 		logger.Info("Collector disabled", "collector", "ntp")
 	}
+	if !config.DeviceConfig {
+		logger.Info("Collector disabled", "collector", "config")
+	}
 
 	return &Collector{
-		config: config,
-		client: client,
-		logger: logger,
+		config:     config,
+		client:     client,
+		logger:     logger,
+		fetches:    &singleflight.Group{},
+		lastStatus: &atomic.Pointer[models.StatusResponse]{},
+		breaker:    newCircuitBreaker(config.BreakerThreshold, config.BreakerCooldown),
 		up: typedDesc{
 			desc: prometheus.NewDesc(
 				prometheus.BuildFQName(MetricNamespace, rootSubsystem, "up"),
@@ -112,13 +187,159 @@ func NewCollector(config Config, client StatusFetcher, logger *slog.Logger) *Col
 			),
 			valueType: prometheus.GaugeValue,
 		},
+		firmware: typedDesc{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(MetricNamespace, rootSubsystem, "firmware_version"),
+				"Meinberg device firmware version encoded as major*10000 + minor*100 + patch (e.g., 7.10.008 = 71008)",
+				[]string{"target", "host"},
+				nil,
+			),
+			valueType: prometheus.GaugeValue,
+		},
+		fetchDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   MetricNamespace,
+			Name:        "fetch_duration_seconds",
+			Help:        "Histogram of the duration of status requests to the Meinberg LTOS device API in seconds",
+			Buckets:     prometheus.DefBuckets,
+			ConstLabels: prometheus.Labels{"target": client.Target()},
+		}),
+		parseErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   MetricNamespace,
+			Name:        "parse_field_errors_total",
+			Help:        "Total number of Meinberg LTOS device API responses that failed to parse, by the JSON path of the offending field (status if unknown)",
+			ConstLabels: prometheus.Labels{"target": client.Target()},
+		}, []string{"field"}),
+		authOK: typedDesc{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(MetricNamespace, rootSubsystem, "auth_ok"),
+				"Indicates if the Meinberg LTOS device accepted the configured credentials (1 = accepted, 0 = rejected with 401 or 403)",
+				[]string{"target"},
+				nil,
+			),
+			valueType: prometheus.GaugeValue,
+		},
+		lastError: typedDesc{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(MetricNamespace, rootSubsystem, "last_error"),
+				"Category of the failure of the current scrape of the Meinberg LTOS device as reason label (timeout, dns, tls, auth, http_5xx, parse, other), absent on success",
+				[]string{"target", "reason"},
+				nil,
+			),
+			valueType: prometheus.GaugeValue,
+		},
+		stale: typedDesc{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(MetricNamespace, rootSubsystem, "stale"),
+				"Indicates if the device metrics are replayed from the last successful scrape because the current one failed (1 = stale, 0 = fresh)",
+				[]string{"target"},
+				nil,
+			),
+			valueType: prometheus.GaugeValue,
+		},
+		fetchDNS: typedDesc{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(MetricNamespace, rootSubsystem, "fetch_dns_seconds"),
+				"Time spent resolving the Meinberg LTOS device hostname during the last status fetch in seconds (0 if a connection was reused)",
+				[]string{"target"},
+				nil,
+			),
+			valueType: prometheus.GaugeValue,
+		},
+		fetchConnect: typedDesc{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(MetricNamespace, rootSubsystem, "fetch_connect_seconds"),
+				"Time spent establishing the TCP connection to the Meinberg LTOS device during the last status fetch in seconds (0 if a connection was reused)",
+				[]string{"target"},
+				nil,
+			),
+			valueType: prometheus.GaugeValue,
+		},
+		fetchTLS: typedDesc{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(MetricNamespace, rootSubsystem, "fetch_tls_handshake_seconds"),
+				"Time spent in the TLS handshake with the Meinberg LTOS device during the last status fetch in seconds (0 if a connection was reused or TLS is not used)",
+				[]string{"target"},
+				nil,
+			),
+			valueType: prometheus.GaugeValue,
+		},
+		circuitOpen: typedDesc{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(MetricNamespace, rootSubsystem, "circuit_open"),
+				"Indicates if fetches from the Meinberg LTOS device are paused after consecutive failures (1 = paused, 0 = fetching)",
+				[]string{"target"},
+				nil,
+			),
+			valueType: prometheus.GaugeValue,
+		},
+		httpStatusCode: typedDesc{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(MetricNamespace, rootSubsystem, "fetch_http_status_code"),
+				"HTTP status code of the last status fetch from the Meinberg LTOS device, also if its body failed to parse (0 if there was no response)",
+				[]string{"target"},
+				nil,
+			),
+			valueType: prometheus.GaugeValue,
+		},
+		responseBytes: typedDesc{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(MetricNamespace, rootSubsystem, "fetch_response_bytes"),
+				"Size of the decompressed body of the last status response from the Meinberg LTOS device in bytes (0 if no body was read)",
+				[]string{"target"},
+				nil,
+			),
+			valueType: prometheus.GaugeValue,
+		},
+		fetchRetries: typedDesc{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(MetricNamespace, rootSubsystem, "fetch_retries_total"),
+				"Total number of requests to the Meinberg LTOS device API retried because the device asked to retry later or sent an empty response",
+				[]string{"target"},
+				nil,
+			),
+			valueType: prometheus.CounterValue,
+		},
+		apiSupported: typedDesc{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(MetricNamespace, rootSubsystem, "api_version_supported"),
+				"Indicates if the device's REST API version is within the range known to be compatible (1 = supported, 0 = unsupported)",
+				[]string{"target", "host"},
+				nil,
+			),
+			valueType: prometheus.GaugeValue,
+		},
 	}
 }
 
+// WithTimeout returns a copy of the collector that bounds each collection by the given timeout
+func (c *Collector) WithTimeout(timeout time.Duration) *Collector {
+	cp := *c
+	cp.config.Timeout = timeout
+	return &cp
+}
+
 func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.up.desc
 	ch <- c.scrapeDuration.desc
 	ch <- c.buildInfo.desc
+	ch <- c.firmware.desc
+	ch <- c.apiSupported.desc
+	c.fetchDuration.Describe(ch)
+	c.parseErrors.Describe(ch)
+	ch <- c.fetchRetries.desc
+	ch <- c.authOK.desc
+	ch <- c.lastError.desc
+	if c.config.OnError == OnErrorStale || c.config.OnError == OnErrorNaN {
+		ch <- c.stale.desc
+	}
+	ch <- c.fetchDNS.desc
+	ch <- c.fetchConnect.desc
+	ch <- c.fetchTLS.desc
+	ch <- c.httpStatusCode.desc
+	ch <- c.responseBytes.desc
+	if c.breaker.enabled() {
+		ch <- c.circuitOpen.desc
+	}
 
 	if c.config.System {
 		describeSystem(ch)
@@ -127,7 +348,7 @@ func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 		describeNotification(ch)
 	}
 	if c.config.Network {
-		describeNetwork(ch)
+		describeNetwork(ch, c.config.NetworkPortDetails)
 	}
 	if c.config.Storage {
 		describeStorage(ch)
@@ -136,12 +357,15 @@ func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 		describeClock(ch)
 	}
 	if c.config.Receiver {
-		describeReceiverGNSS(ch)
+		describeReceiverGNSS(ch, c.config.GNSSPosition)
 		describeReceiverDCF77(ch)
 	}
 	if c.config.NTP {
 		describeNTP(ch)
 	}
+	if c.config.DeviceConfig {
+		describeConfig(ch)
+	}
 }
 
 func (c *Collector) Collect(ch chan<- prometheus.Metric) {
@@ -161,18 +385,90 @@ func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 
 	logger.Debug("Collecting metrics from Meinberg LTOS device", "target", c.client.Target())
 
-	status, err := c.client.FetchStatus(ctx, logger)
+	if c.breaker.isOpen() {
+		logger.Debug("Skipping fetch while the circuit breaker is open", "target", c.client.Target())
+		ch <- c.circuitOpen.mustNewConstMetric(1, c.client.Target())
+		// Cumulative metrics are kept so their series continue through the cooldown
+		c.fetchDuration.Collect(ch)
+		c.parseErrors.Collect(ch)
+		if rc, ok := c.client.(retryCounter); ok {
+			ch <- c.fetchRetries.mustNewConstMetric(float64(rc.Retries()), c.client.Target())
+		}
+		c.collectLastStatus(ch, logger)
+		return
+	}
+
+	fetchStart := time.Now()
+	status, phases, err := c.fetchStatus(ctx, logger)
+	c.fetchDuration.Observe(time.Since(fetchStart).Seconds())
+	c.fetchDuration.Collect(ch)
+	dns, connect, tlsHandshake := phases.seconds()
+	ch <- c.fetchDNS.mustNewConstMetric(dns, c.client.Target())
+	ch <- c.fetchConnect.mustNewConstMetric(connect, c.client.Target())
+	ch <- c.fetchTLS.mustNewConstMetric(tlsHandshake, c.client.Target())
+	if rc, ok := c.client.(retryCounter); ok {
+		ch <- c.fetchRetries.mustNewConstMetric(float64(rc.Retries()), c.client.Target())
+	}
+	if sc, ok := c.client.(statusCodeReporter); ok {
+		ch <- c.httpStatusCode.mustNewConstMetric(float64(sc.LastStatusCode()), c.client.Target())
+	}
+	if rs, ok := c.client.(responseSizeReporter); ok {
+		ch <- c.responseBytes.mustNewConstMetric(float64(rs.LastResponseBytes()), c.client.Target())
+	}
+	if c.breaker.enabled() {
+		ch <- c.circuitOpen.mustNewConstMetric(boolToFloat64(c.breaker.isOpen()), c.client.Target())
+	}
 	if err != nil {
-		logger.Warn("Failed to fetch Meinberg LTOS device status", "error", err)
+		reason := ltosapi.ErrorReason(err)
+		logger.Warn("Failed to fetch Meinberg LTOS device status", "error", err, "reason", reason)
+		ch <- c.lastError.mustNewConstMetric(1, c.client.Target(), reason)
+		if errors.Is(err, ltosapi.ErrUnauthorized) {
+			ch <- c.authOK.mustNewConstMetric(0, c.client.Target())
+		}
+		if errors.Is(err, ltosapi.ErrUnmarshalResponse) {
+			c.parseErrors.WithLabelValues(unmarshalErrorField(err)).Inc()
+		}
+		c.parseErrors.Collect(ch)
+		c.collectLastStatus(ch, logger)
 		return
 	}
 
 	up = 1.0
-	host := status.SystemInformation.Hostname
+	ch <- c.authOK.mustNewConstMetric(1, c.client.Target())
+	if c.config.OnError == OnErrorStale || c.config.OnError == OnErrorNaN {
+		c.lastStatus.Store(status)
+		ch <- c.stale.mustNewConstMetric(0, c.client.Target())
+	}
+
+	c.parseErrors.Collect(ch)
+
+	c.collectStatus(ch, status, logger)
+	if c.config.DeviceConfig {
+		c.collectConfig(ctx, ch, c.deviceHost(status), logger)
+	}
+}
+
+// collectStatus emits the device metrics derived from the given status
+func (c *Collector) collectStatus(ch chan<- prometheus.Metric, status *models.StatusResponse, logger *slog.Logger) {
+	host := c.deviceHost(status)
 	ch <- c.buildInfo.mustNewConstMetric(1.0, c.client.Target(), host, status.Data.RestAPI.Version, status.SystemInformation.Version)
 
+	apiSupported := status.Data.RestAPI.IsSupported()
+	if !apiSupported {
+		logger.Warn("Unsupported Meinberg LTOS REST API version, parsing may be incomplete", "api_version", status.Data.RestAPI.Version)
+	}
+	ch <- c.apiSupported.mustNewConstMetric(boolToFloat64(apiSupported), c.client.Target(), host)
+
+	if fw, err := models.ParseFirmwareVersion(status.SystemInformation.Version); err != nil {
+		logger.Debug("Failed to parse firmware version", "version", status.SystemInformation.Version, "error", err)
+	} else {
+		ch <- c.firmware.mustNewConstMetric(fw.Numeric(), c.client.Target(), host)
+	}
+
+	slots := status.Data.Slots()
+
 	if c.config.System {
-		c.collectSystem(ch, host, status.SystemInformation, status.Data.System, status.Data.Chassis.Slots)
+		c.collectSystem(ch, host, status.SystemInformation, status.Data.System, status.Changes, slots)
 	}
 	if c.config.Notification {
 		c.collectNotification(ch, host, status.Data.Notification.Events)
@@ -184,15 +480,123 @@ func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 		c.collectStorage(ch, host, status.Data.System.Mounts)
 	}
 	if c.config.NTP {
-		c.collectNTP(ch, host, status.Data.NTP)
+		c.collectNTP(ch, host, status.Data.NTP, status.Data.Services, logger)
 	}
 	if c.config.Clock {
-		c.collectClock(ch, host, status.Data.Chassis.Slots)
+		c.collectClock(ch, host, slots)
 	}
 	if c.config.Receiver {
-		c.collectReceiverGNSS(ch, host, status.Data.Chassis.Slots)
-		c.collectReceiverDCF77(ch, host, status.Data.Chassis.Slots)
+		c.collectReceiverGNSS(ch, host, slots)
+		c.collectReceiverGNSSAntennaEvents(ch, host, slots, status.Data.Notification.Events)
+		c.collectReceiverDCF77(ch, host, slots)
 	}
 
 	logger.Debug("Done collecting metrics from Meinberg LTOS device", "target", c.client.Target(), "host", host)
 }
+
+// deviceHost returns the host label of the device metrics derived from the given status
+func (c *Collector) deviceHost(status *models.StatusResponse) string {
+	if c.config.HostLabel == HostLabelTarget {
+		return hostLabel("", c.client.Target())
+	}
+	return hostLabel(status.SystemInformation.Hostname, c.client.Target())
+}
+
+// collectLastStatus replays the device metrics of the last successful scrape after a failed one, depending on
+// OnError either with their last known values or with NaN
+func (c *Collector) collectLastStatus(ch chan<- prometheus.Metric, logger *slog.Logger) {
+	if c.config.OnError != OnErrorStale && c.config.OnError != OnErrorNaN {
+		return
+	}
+
+	status := c.lastStatus.Load()
+	if status == nil {
+		return
+	}
+
+	ch <- c.stale.mustNewConstMetric(1, c.client.Target())
+	logger.Debug("Replaying metrics of the last successful scrape", "target", c.client.Target(), "mode", c.config.OnError)
+
+	if c.config.OnError == OnErrorStale {
+		c.collectStatus(ch, status, logger)
+		return
+	}
+
+	nanCh := make(chan prometheus.Metric)
+	go func() {
+		defer close(nanCh)
+		c.collectStatus(nanCh, status, logger)
+	}()
+	for m := range nanCh {
+		ch <- nanMetric{m}
+	}
+}
+
+// nanMetric reports the series of the wrapped metric with a NaN value
+type nanMetric struct {
+	prometheus.Metric
+}
+
+func (m nanMetric) Write(out *dto.Metric) error {
+	if err := m.Metric.Write(out); err != nil {
+		return err
+	}
+	nan := math.NaN()
+	switch {
+	case out.Gauge != nil:
+		out.Gauge.Value = &nan
+	case out.Counter != nil:
+		out.Counter.Value = &nan
+	case out.Untyped != nil:
+		out.Untyped.Value = &nan
+	}
+	return nil
+}
+
+// fetchResult is the outcome of a status fetch shared between concurrent collections
+type fetchResult struct {
+	status *models.StatusResponse
+	phases *fetchPhases
+}
+
+// testHookFetchWaiting is called once a collection waits for a fetch, either its own or one already in flight
+var testHookFetchWaiting = func() {}
+
+// fetchStatus fetches the device status, sharing the result with collections of the same target that are
+// already in flight instead of sending another request. The connection phase timings of the fetch are returned
+// even if it failed.
+//
+// The shared fetch runs with the context of the collection that started it. A collection joining it stops
+// waiting when its own context is done, but cannot shorten the fetch itself.
+func (c *Collector) fetchStatus(ctx context.Context, logger *slog.Logger) (*models.StatusResponse, *fetchPhases, error) {
+	results := c.fetches.DoChan(c.client.Target(), func() (any, error) {
+		phases := newFetchPhases()
+		status, err := c.client.FetchStatus(httptrace.WithClientTrace(ctx, phases.clientTrace()), logger)
+		if c.breaker.record(err) {
+			logger.Warn("Pausing fetches after consecutive failures", "target", c.client.Target(),
+				"failures", c.config.BreakerThreshold, "cooldown", c.config.BreakerCooldown)
+		}
+		return fetchResult{status: status, phases: phases}, err
+	})
+	testHookFetchWaiting()
+
+	select {
+	case r := <-results:
+		if r.Shared {
+			logger.Debug("Shared Meinberg LTOS device status with a concurrent collection", "target", c.client.Target())
+		}
+		res := r.Val.(fetchResult)
+		return res.status, res.phases, r.Err
+	case <-ctx.Done():
+		return nil, newFetchPhases(), ctx.Err()
+	}
+}
+
+// unmarshalErrorField returns the JSON path of the field that failed to unmarshal, or "status" if it is unknown
+func unmarshalErrorField(err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return typeErr.Field
+	}
+	return "status"
+}
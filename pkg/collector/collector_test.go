@@ -9,11 +9,14 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/expfmt"
 	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/collector"
 	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/ltosapi"
@@ -35,29 +38,20 @@ func TestCollector(t *testing.T) {
 		metricsFile := strings.TrimSuffix(jsonFile, ".json") + ".metrics"
 
 		t.Run(name, func(t *testing.T) {
-			jsonData, err := os.ReadFile(jsonFile)
-			if err != nil {
-				t.Fatalf("failed to read %s: %v", jsonFile, err)
-			}
-
-			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				w.Header().Set("Content-Type", "application/json")
-				if _, err := w.Write(jsonData); err != nil {
-					t.Errorf("failed to write mock response: %v", err)
-				}
-			}))
-			defer srv.Close()
+			srv := newFixtureServer(t, jsonFile)
 
-			client, _ := ltosapi.NewClient(srv.URL, "", "", false)
+			client, _ := ltosapi.NewClient(srv.URL)
 			cfg := collector.Config{
-				Timeout:      5 * time.Second,
-				System:       true,
-				Notification: true,
-				Network:      true,
-				Storage:      true,
-				Clock:        true,
-				Receiver:     true,
-				NTP:          true,
+				Timeout:            5 * time.Second,
+				System:             true,
+				Notification:       true,
+				Network:            true,
+				NetworkPortDetails: true,
+				Storage:            true,
+				Clock:              true,
+				Receiver:           true,
+				GNSSPosition:       true,
+				NTP:                true,
 			}
 			c := collector.NewCollector(cfg, client, slog.New(slog.DiscardHandler))
 
@@ -85,6 +79,535 @@ func TestCollector(t *testing.T) {
 	}
 }
 
+func TestCollector_GNSSPositionDisabled(t *testing.T) {
+	srv := newFixtureServer(t, "../../tests/testdata/m600-gps.json")
+
+	client, _ := ltosapi.NewClient(srv.URL)
+	cfg := collector.Config{Timeout: 5 * time.Second, Receiver: true}
+	c := collector.NewCollector(cfg, client, slog.New(slog.DiscardHandler))
+
+	// The position metrics are compared against nothing, so they must be absent
+	want := `
+# HELP meinberg_ltos_clock_receiver_gnss_satellites_good Number of good satellites for the GNSS receiver
+# TYPE meinberg_ltos_clock_receiver_gnss_satellites_good gauge
+meinberg_ltos_clock_receiver_gnss_satellites_good{chassis="0",clock_id="clk1",host="mbg1.time.example.com"} 9
+`
+	compareMetrics(t, c, want, "clock_receiver_gnss_satellites_good", "clock_receiver_gnss_latitude_degrees",
+		"clock_receiver_gnss_longitude_degrees", "clock_receiver_gnss_altitude_meters", "clock_receiver_gnss_position_info")
+}
+
+func TestCollector_ParseFieldErrors(t *testing.T) {
+	srv := newStatusServer(t, `{
+		"system-information": {"hostname": "mbg1"},
+		"data": {"rest-api": {"api-version": 10}}
+	}`)
+
+	client, _ := ltosapi.NewClient(srv.URL)
+	cfg := collector.Config{Timeout: 5 * time.Second, System: true}
+	c := collector.NewCollector(cfg, client, slog.New(slog.DiscardHandler))
+
+	want := fmt.Sprintf(`
+# HELP meinberg_ltos_parse_field_errors_total Total number of Meinberg LTOS device API responses that failed to parse, by the JSON path of the offending field (status if unknown)
+# TYPE meinberg_ltos_parse_field_errors_total counter
+meinberg_ltos_parse_field_errors_total{field="data.rest-api.api-version",target=%[1]q} 1
+# HELP meinberg_ltos_last_error Category of the failure of the current scrape of the Meinberg LTOS device as reason label (timeout, dns, tls, auth, http_5xx, parse, other), absent on success
+# TYPE meinberg_ltos_last_error gauge
+meinberg_ltos_last_error{reason="parse",target=%[1]q} 1
+# HELP meinberg_ltos_up Indicates if the Meinberg LTOS device is reachable (1 = up, 0 = down)
+# TYPE meinberg_ltos_up gauge
+meinberg_ltos_up{target=%[1]q} 0
+`, srv.URL)
+	compareMetrics(t, c, want, "parse_field_errors_total", "last_error", "up")
+}
+
+func TestCollector_UnparseableFirmwareVersion(t *testing.T) {
+	srv := newStatusServer(t, `{
+		"system-information": {"hostname": "mbg1", "version": "custom-build"},
+		"data": {"rest-api": {"api-version": "20.05.013"}}
+	}`)
+
+	client, _ := ltosapi.NewClient(srv.URL)
+	cfg := collector.Config{Timeout: 5 * time.Second, System: true}
+	c := collector.NewCollector(cfg, client, slog.New(slog.DiscardHandler))
+
+	// Neither a parse error nor a firmware version is reported for a version string that does not parse
+	want := fmt.Sprintf(`
+# HELP meinberg_ltos_up Indicates if the Meinberg LTOS device is reachable (1 = up, 0 = down)
+# TYPE meinberg_ltos_up gauge
+meinberg_ltos_up{target=%q} 1
+`, srv.URL)
+	compareMetrics(t, c, want, "parse_field_errors_total", "firmware_version", "up")
+}
+
+func TestCollector_AuthFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client, _ := ltosapi.NewClient(srv.URL)
+	c := collector.NewCollector(collector.Config{Timeout: 5 * time.Second}, client, slog.New(slog.DiscardHandler))
+
+	want := fmt.Sprintf(`
+# HELP meinberg_ltos_auth_ok Indicates if the Meinberg LTOS device accepted the configured credentials (1 = accepted, 0 = rejected with 401 or 403)
+# TYPE meinberg_ltos_auth_ok gauge
+meinberg_ltos_auth_ok{target=%[1]q} 0
+# HELP meinberg_ltos_last_error Category of the failure of the current scrape of the Meinberg LTOS device as reason label (timeout, dns, tls, auth, http_5xx, parse, other), absent on success
+# TYPE meinberg_ltos_last_error gauge
+meinberg_ltos_last_error{reason="auth",target=%[1]q} 1
+# HELP meinberg_ltos_up Indicates if the Meinberg LTOS device is reachable (1 = up, 0 = down)
+# TYPE meinberg_ltos_up gauge
+meinberg_ltos_up{target=%[1]q} 0
+`, srv.URL)
+	compareMetrics(t, c, want, "auth_ok", "last_error", "up")
+}
+
+func TestCollector_MissingHostname(t *testing.T) {
+	srv := newStatusServer(t, `{
+		"system-information": {"version": "fw_7.10.008", "model": "LANTIME M600"},
+		"data": {"rest-api": {"api-version": "20.05.013"}}
+	}`)
+
+	client, _ := ltosapi.NewClient(srv.URL)
+	cfg := collector.Config{Timeout: 5 * time.Second, System: true}
+	c := collector.NewCollector(cfg, client, slog.New(slog.DiscardHandler))
+
+	want := fmt.Sprintf(`
+# HELP meinberg_ltos_system_info Meinberg system information as labels (e.g., model, serial number, host)
+# TYPE meinberg_ltos_system_info gauge
+meinberg_ltos_system_info{host="127.0.0.1",model="LANTIME M600",serial_number=""} 1
+# HELP meinberg_ltos_firmware_version Meinberg device firmware version encoded as major*10000 + minor*100 + patch (e.g., 7.10.008 = 71008)
+# TYPE meinberg_ltos_firmware_version gauge
+meinberg_ltos_firmware_version{host="127.0.0.1",target=%q} 71008
+`, srv.URL)
+	compareMetrics(t, c, want, "system_info", "firmware_version")
+}
+
+func TestCollector_HostLabelTarget(t *testing.T) {
+	srv := newFixtureServer(t, "../../tests/testdata/m600-gps.json")
+
+	client, _ := ltosapi.NewClient(srv.URL)
+	cfg := collector.Config{Timeout: 5 * time.Second, HostLabel: collector.HostLabelTarget, System: true}
+	c := collector.NewCollector(cfg, client, slog.New(slog.DiscardHandler))
+
+	got := gatherMetrics(t, c)
+	if !strings.Contains(got, metricsPrefix+"system_info{") {
+		t.Fatalf("expected system_info metric, got:\n%s", got)
+	}
+	if strings.Contains(got, "mbg1.time.example.com") {
+		t.Errorf("expected device hostname to be replaced by target host, got:\n%s", got)
+	}
+}
+
+func TestCollector_NetworkPortStatistics(t *testing.T) {
+	srv := newStatusServer(t, `{
+		"system-information": {"hostname": "mbg1"},
+		"data": {
+			"rest-api": {"api-version": "20.05.013"},
+			"network": {"ports": [
+				{"object-id": "lan0", "link": true, "speed": "1000", "statistics": {
+					"rx-bytes": 1000, "tx-bytes": 2000, "rx-packets": 10, "tx-packets": 20,
+					"rx-errors": 1, "tx-errors": 2, "rx-dropped": 3, "tx-dropped": 4
+				}},
+				{"object-id": "lan1", "link": true, "speed": "100", "statistics": {
+					"rx-bytes": 5000, "tx-bytes": 6000, "rx-packets": 50, "tx-packets": 60,
+					"rx-errors": 0, "tx-errors": 0, "rx-dropped": 7, "tx-dropped": 8
+				}}
+			]}
+		}
+	}`)
+
+	client, _ := ltosapi.NewClient(srv.URL)
+	cfg := collector.Config{Timeout: 5 * time.Second, Network: true, NetworkPortDetails: true}
+	c := collector.NewCollector(cfg, client, slog.New(slog.DiscardHandler))
+
+	want := `
+# HELP meinberg_ltos_network_port_rx_bytes_total Total bytes received on the network port
+# TYPE meinberg_ltos_network_port_rx_bytes_total counter
+meinberg_ltos_network_port_rx_bytes_total{host="mbg1",port="lan0"} 1000
+meinberg_ltos_network_port_rx_bytes_total{host="mbg1",port="lan1"} 5000
+# HELP meinberg_ltos_network_port_tx_bytes_total Total bytes transmitted on the network port
+# TYPE meinberg_ltos_network_port_tx_bytes_total counter
+meinberg_ltos_network_port_tx_bytes_total{host="mbg1",port="lan0"} 2000
+meinberg_ltos_network_port_tx_bytes_total{host="mbg1",port="lan1"} 6000
+# HELP meinberg_ltos_network_port_rx_packets_total Total packets received on the network port
+# TYPE meinberg_ltos_network_port_rx_packets_total counter
+meinberg_ltos_network_port_rx_packets_total{host="mbg1",port="lan0"} 10
+meinberg_ltos_network_port_rx_packets_total{host="mbg1",port="lan1"} 50
+# HELP meinberg_ltos_network_port_tx_packets_total Total packets transmitted on the network port
+# TYPE meinberg_ltos_network_port_tx_packets_total counter
+meinberg_ltos_network_port_tx_packets_total{host="mbg1",port="lan0"} 20
+meinberg_ltos_network_port_tx_packets_total{host="mbg1",port="lan1"} 60
+# HELP meinberg_ltos_network_port_rx_errors_total Total receive errors on the network port
+# TYPE meinberg_ltos_network_port_rx_errors_total counter
+meinberg_ltos_network_port_rx_errors_total{host="mbg1",port="lan0"} 1
+meinberg_ltos_network_port_rx_errors_total{host="mbg1",port="lan1"} 0
+# HELP meinberg_ltos_network_port_tx_errors_total Total transmit errors on the network port
+# TYPE meinberg_ltos_network_port_tx_errors_total counter
+meinberg_ltos_network_port_tx_errors_total{host="mbg1",port="lan0"} 2
+meinberg_ltos_network_port_tx_errors_total{host="mbg1",port="lan1"} 0
+# HELP meinberg_ltos_network_port_rx_dropped_total Total received packets dropped on the network port
+# TYPE meinberg_ltos_network_port_rx_dropped_total counter
+meinberg_ltos_network_port_rx_dropped_total{host="mbg1",port="lan0"} 3
+meinberg_ltos_network_port_rx_dropped_total{host="mbg1",port="lan1"} 7
+# HELP meinberg_ltos_network_port_tx_dropped_total Total transmitted packets dropped on the network port
+# TYPE meinberg_ltos_network_port_tx_dropped_total counter
+meinberg_ltos_network_port_tx_dropped_total{host="mbg1",port="lan0"} 4
+meinberg_ltos_network_port_tx_dropped_total{host="mbg1",port="lan1"} 8
+# HELP meinberg_ltos_network_ports_up Number of network ports with link up
+# TYPE meinberg_ltos_network_ports_up gauge
+meinberg_ltos_network_ports_up{host="mbg1"} 2
+`
+	compareMetrics(t, c, want,
+		"network_port_rx_bytes_total", "network_port_tx_bytes_total",
+		"network_port_rx_packets_total", "network_port_tx_packets_total",
+		"network_port_rx_errors_total", "network_port_tx_errors_total",
+		"network_port_rx_dropped_total", "network_port_tx_dropped_total",
+		"network_ports_up")
+}
+
+func TestCollector_NTPPeersSkipped(t *testing.T) {
+	peers := make([]string, 66)
+	for i := range peers {
+		peers[i] = fmt.Sprintf(`{"association-id": %d, "id": "192.0.2.%d", "name": "peer%d", "refid": "GPS", "reach": 255}`, i+1, i+1, i+1)
+	}
+	srv := newStatusServer(t, `{
+		"system-information": {"hostname": "mbg1"},
+		"data": {
+			"rest-api": {"api-version": "20.05.013"},
+			"ntp": [`+strings.Join(peers, ",")+`]
+		}
+	}`)
+
+	client, _ := ltosapi.NewClient(srv.URL)
+	cfg := collector.Config{Timeout: 5 * time.Second, NTP: true}
+	c := collector.NewCollector(cfg, client, slog.New(slog.DiscardHandler))
+
+	want := `
+# HELP meinberg_ltos_ntp_peers_skipped Number of NTP peer associations without peer metrics because the device reports more than 64
+# TYPE meinberg_ltos_ntp_peers_skipped gauge
+meinberg_ltos_ntp_peers_skipped{host="mbg1"} 2
+`
+	compareMetrics(t, c, want, "ntp_peers_skipped")
+
+	if n := testutil.CollectAndCount(c, metricsPrefix+"ntp_peer_reach"); n != 64 {
+		t.Errorf("got %d ntp_peer_reach series, want 64", n)
+	}
+}
+
+func TestCollector_CPULoadPeriods(t *testing.T) {
+	srv := newStatusServer(t, `{
+		"system-information": {"hostname": "mbg1"},
+		"data": {
+			"rest-api": {"api-version": "20.05.013"},
+			"system": {"cpuload": "0.48 0.66 0.57 2/99 25157"}
+		}
+	}`)
+
+	client, _ := ltosapi.NewClient(srv.URL)
+	cfg := collector.Config{Timeout: 5 * time.Second, System: true}
+	c := collector.NewCollector(cfg, client, slog.New(slog.DiscardHandler))
+
+	want := `
+# HELP meinberg_ltos_system_cpu_load_avg CPU load averaged over 1, 5, and 15 minutes
+# TYPE meinberg_ltos_system_cpu_load_avg gauge
+meinberg_ltos_system_cpu_load_avg{host="mbg1",period="1"} 0.48
+meinberg_ltos_system_cpu_load_avg{host="mbg1",period="5"} 0.66
+meinberg_ltos_system_cpu_load_avg{host="mbg1",period="15"} 0.57
+`
+	compareMetrics(t, c, want, "system_cpu_load_avg")
+}
+
+func TestCollector_ConcurrentScrapesShareFetch(t *testing.T) {
+	jsonData, err := os.ReadFile(filepath.Join("..", "..", "tests", "testdata", "m600-gps.json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	var hits atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(jsonData)
+	}))
+	defer srv.Close()
+
+	client, _ := ltosapi.NewClient(srv.URL)
+	c := collector.NewCollector(collector.Config{Timeout: 5 * time.Second, System: true}, client, slog.New(slog.DiscardHandler))
+
+	const scrapes = 2
+	waiting := make(chan struct{}, scrapes)
+	collector.SetFetchWaitingHook(t, func() { waiting <- struct{}{} })
+
+	errs := make(chan error, scrapes)
+	for range scrapes {
+		go func() {
+			reg := prometheus.NewPedanticRegistry()
+			reg.MustRegister(c)
+			_, err := reg.Gather()
+			errs <- err
+		}()
+	}
+
+	// Hold the first request until both scrapes wait for it
+	for range scrapes {
+		<-waiting
+	}
+	close(release)
+
+	for range scrapes {
+		if err := <-errs; err != nil {
+			t.Fatalf("failed to gather metrics: %v", err)
+		}
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("device received %d requests, want 1", got)
+	}
+}
+
+func TestCollector_FetchPhases(t *testing.T) {
+	jsonData, err := os.ReadFile(filepath.Join("..", "..", "tests", "testdata", "m600-gps.json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(jsonData)
+	}))
+	defer srv.Close()
+
+	client, _ := ltosapi.NewClient(srv.URL, ltosapi.WithInsecureSkipVerify(true))
+	c := collector.NewCollector(collector.Config{Timeout: 5 * time.Second}, client, slog.New(slog.DiscardHandler))
+
+	got := gatherMetrics(t, c)
+
+	for _, name := range []string{"fetch_connect_seconds", "fetch_tls_handshake_seconds"} {
+		prefix := fmt.Sprintf("%s%s{target=%q} ", metricsPrefix, name, srv.URL)
+		var value float64
+		for _, line := range strings.Split(got, "\n") {
+			if v, ok := strings.CutPrefix(line, prefix); ok {
+				value, _ = strconv.ParseFloat(v, 64)
+			}
+		}
+		if value <= 0 {
+			t.Errorf("%s = %v, want > 0 for a new TLS connection", name, value)
+		}
+	}
+}
+
+func TestCollector_OnError(t *testing.T) {
+	jsonData, err := os.ReadFile(filepath.Join("..", "..", "tests", "testdata", "m600-gps.json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	// The replayed metrics, formatted with the target as first argument; dropped metrics are compared against nothing
+	tests := []struct {
+		onError string
+		want    string
+	}{
+		{
+			onError: collector.OnErrorDrop,
+		},
+		{
+			onError: collector.OnErrorStale,
+			want: `
+# HELP meinberg_ltos_stale Indicates if the device metrics are replayed from the last successful scrape because the current one failed (1 = stale, 0 = fresh)
+# TYPE meinberg_ltos_stale gauge
+meinberg_ltos_stale{target=%[1]q} 1
+# HELP meinberg_ltos_system_uptime_seconds System uptime in seconds
+# TYPE meinberg_ltos_system_uptime_seconds gauge
+meinberg_ltos_system_uptime_seconds{host="mbg1.time.example.com"} 130988.25
+`,
+		},
+		{
+			onError: collector.OnErrorNaN,
+			want: `
+# HELP meinberg_ltos_stale Indicates if the device metrics are replayed from the last successful scrape because the current one failed (1 = stale, 0 = fresh)
+# TYPE meinberg_ltos_stale gauge
+meinberg_ltos_stale{target=%[1]q} 1
+# HELP meinberg_ltos_system_uptime_seconds System uptime in seconds
+# TYPE meinberg_ltos_system_uptime_seconds gauge
+meinberg_ltos_system_uptime_seconds{host="mbg1.time.example.com"} NaN
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.onError, func(t *testing.T) {
+			var failing atomic.Bool
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if failing.Load() {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write(jsonData)
+			}))
+			defer srv.Close()
+
+			client, _ := ltosapi.NewClient(srv.URL)
+			cfg := collector.Config{Timeout: 5 * time.Second, OnError: tt.onError, System: true}
+			c := collector.NewCollector(cfg, client, slog.New(slog.DiscardHandler))
+
+			_ = gatherMetrics(t, c)
+			failing.Store(true)
+
+			want := fmt.Sprintf(`
+# HELP meinberg_ltos_up Indicates if the Meinberg LTOS device is reachable (1 = up, 0 = down)
+# TYPE meinberg_ltos_up gauge
+meinberg_ltos_up{target=%[1]q} 0
+# HELP meinberg_ltos_fetch_http_status_code HTTP status code of the last status fetch from the Meinberg LTOS device, also if its body failed to parse (0 if there was no response)
+# TYPE meinberg_ltos_fetch_http_status_code gauge
+meinberg_ltos_fetch_http_status_code{target=%[1]q} 503
+`+tt.want, srv.URL)
+			compareMetrics(t, c, want, "up", "fetch_http_status_code", "stale", "system_uptime_seconds")
+		})
+	}
+}
+
+func TestCollector_CircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client, _ := ltosapi.NewClient(srv.URL)
+	cfg := collector.Config{Timeout: 5 * time.Second, BreakerThreshold: 2, BreakerCooldown: time.Hour}
+	c := collector.NewCollector(cfg, client, slog.New(slog.DiscardHandler))
+
+	const circuitOpen = `
+# HELP meinberg_ltos_circuit_open Indicates if fetches from the Meinberg LTOS device are paused after consecutive failures (1 = paused, 0 = fetching)
+# TYPE meinberg_ltos_circuit_open gauge
+meinberg_ltos_circuit_open{target=%[1]q} %[2]d
+`
+	// The first failure stays below the threshold
+	compareMetrics(t, c, fmt.Sprintf(circuitOpen, srv.URL, 0), "circuit_open")
+
+	for range 2 {
+		_ = gatherMetrics(t, c)
+	}
+
+	if n := requests.Load(); n != 2 {
+		t.Errorf("expected fetches to stop after 2 failures, device got %d requests", n)
+	}
+
+	want := fmt.Sprintf(circuitOpen+`# HELP meinberg_ltos_up Indicates if the Meinberg LTOS device is reachable (1 = up, 0 = down)
+# TYPE meinberg_ltos_up gauge
+meinberg_ltos_up{target=%[1]q} 0
+`, srv.URL, 1)
+	compareMetrics(t, c, want, "circuit_open", "up")
+}
+
+func TestCollector_DeviceConfig(t *testing.T) {
+	jsonData, err := os.ReadFile(filepath.Join("..", "..", "tests", "testdata", "m600-gps.json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		config func(w http.ResponseWriter)
+		want   string
+	}{
+		{
+			name: "available",
+			config: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"data": {"system": {"timezone": "Europe/Zurich"}, "ntp": {"stratum": 1}}}`))
+			},
+			want: `
+# HELP meinberg_ltos_device_config_info Configured settings of the Meinberg device as labels, empty if not configured
+# TYPE meinberg_ltos_device_config_info gauge
+meinberg_ltos_device_config_info{host="mbg1.time.example.com",ptp_profile="",timezone="Europe/Zurich"} 1
+# HELP meinberg_ltos_device_config_ntp_stratum Configured NTP stratum of the Meinberg device
+# TYPE meinberg_ltos_device_config_ntp_stratum gauge
+meinberg_ltos_device_config_ntp_stratum{host="mbg1.time.example.com"} 1
+`,
+		},
+		{
+			name:   "forbidden",
+			config: func(w http.ResponseWriter) { w.WriteHeader(http.StatusForbidden) },
+		},
+		{
+			name:   "disabled",
+			config: func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/status", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write(jsonData)
+			})
+			mux.HandleFunc("/api/config", func(w http.ResponseWriter, r *http.Request) { tt.config(w) })
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			client, _ := ltosapi.NewClient(srv.URL)
+			cfg := collector.Config{Timeout: 5 * time.Second, DeviceConfig: true}
+			c := collector.NewCollector(cfg, client, slog.New(slog.DiscardHandler))
+
+			// The status metrics are emitted whether or not the config is available
+			want := fmt.Sprintf(`
+# HELP meinberg_ltos_up Indicates if the Meinberg LTOS device is reachable (1 = up, 0 = down)
+# TYPE meinberg_ltos_up gauge
+meinberg_ltos_up{target=%q} 1
+`, srv.URL) + tt.want
+			compareMetrics(t, c, want, "up", "device_config_info", "device_config_ntp_stratum")
+		})
+	}
+}
+
+// newFixtureServer serves the given test data file as /api/status response
+// from a mock LTOS API server, which is closed at the end of the test.
+func newFixtureServer(t *testing.T, path string) *httptest.Server {
+	t.Helper()
+
+	jsonData, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+
+	return newStatusServer(t, string(jsonData))
+}
+
+// newStatusServer serves body as /api/status response from a mock LTOS API
+// server, which is closed at the end of the test.
+func newStatusServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Errorf("failed to write mock response: %v", err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// compareMetrics collects the metrics with the given names, without namespace,
+// from the collector and compares them to want in text exposition format.
+// Metrics named but missing from want must be absent.
+func compareMetrics(t *testing.T, c *collector.Collector, want string, names ...string) {
+	t.Helper()
+
+	for i, name := range names {
+		names[i] = metricsPrefix + name
+	}
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want), names...); err != nil {
+		t.Error(err)
+	}
+}
+
 // gatherMetrics collects all metrics from the given collector and returns
 // them in Prometheus text exposition format.
 func gatherMetrics(t *testing.T, c *collector.Collector) string {
@@ -115,9 +638,18 @@ type metricBlock struct {
 	samples []string
 }
 
+// volatileMetrics are the names, without namespace, of metrics whose values vary between runs
+var volatileMetrics = []string{
+	"scrape_duration_seconds",
+	"fetch_duration_seconds",
+	"fetch_dns_seconds",
+	"fetch_connect_seconds",
+	"fetch_tls_handshake_seconds",
+}
+
 // filterMetrics keeps only meinberg_ltos_ metrics, normalises the dynamic
-// target URL to a fixed placeholder, and replaces the scrape_duration_seconds
-// value with 0 since it varies between runs. The output is sorted by metric
+// target URL to a fixed placeholder, and replaces the values of the duration
+// metrics in volatileMetrics with 0 since they vary between runs. The output is sorted by metric
 // name for deterministic comparison.
 func filterMetrics(input string, target string) string {
 	input = strings.ReplaceAll(input, target, "http://localhost")
@@ -146,14 +678,17 @@ func filterMetrics(input string, target string) string {
 			continue
 		}
 
-		// Replace scrape duration value with placeholder
-		if strings.HasPrefix(line, metricsPrefix+"scrape_duration_seconds") {
-			if idx := strings.LastIndexByte(line, ' '); idx > 0 {
-				line = line[:idx] + " 0"
+		// Replace duration values with placeholder
+		for _, volatile := range volatileMetrics {
+			if strings.HasPrefix(line, metricsPrefix+volatile) {
+				if idx := strings.LastIndexByte(line, ' '); idx > 0 {
+					line = line[:idx] + " 0"
+				}
+				break
 			}
 		}
 
-		name := metricName(line)
+		name := histogramBaseName(blocks, metricName(line))
 		b := getOrCreateBlock(blocks, name, &names)
 		b.samples = append(b.samples, line)
 	}
@@ -196,6 +731,19 @@ func getOrCreateBlock(blocks map[string]*metricBlock, name string, order *[]stri
 	return b
 }
 
+// histogramBaseName maps the _bucket, _sum and _count samples of a histogram
+// to the metric name of its HELP and TYPE lines.
+func histogramBaseName(blocks map[string]*metricBlock, name string) string {
+	for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+		if base, ok := strings.CutSuffix(name, suffix); ok {
+			if _, exists := blocks[base]; exists {
+				return base
+			}
+		}
+	}
+	return name
+}
+
 // metricName extracts the metric name from a sample line, i.e. everything
 // before the first '{' or ' '.
 func metricName(line string) string {
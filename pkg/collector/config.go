@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/ltosapi"
+)
+
+const configSubsystem = "device_config"
+
+var (
+	configInfo = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, configSubsystem, "info"),
+			"Configured settings of the Meinberg device as labels, empty if not configured",
+			[]string{"host", "timezone", "ptp_profile"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	configNTPStratum = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, configSubsystem, "ntp_stratum"),
+			"Configured NTP stratum of the Meinberg device",
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+)
+
+func describeConfig(ch chan<- *prometheus.Desc) {
+	ch <- configInfo.desc
+	ch <- configNTPStratum.desc
+}
+
+// collectConfig fetches the device configuration and emits the configured settings. Devices that disable the
+// endpoint or forbid it for the configured user are expected, so only the status metrics are emitted for them.
+func (c *Collector) collectConfig(ctx context.Context, ch chan<- prometheus.Metric, host string, logger *slog.Logger) {
+	fetcher, ok := c.client.(configFetcher)
+	if !ok {
+		return
+	}
+
+	config, err := fetcher.FetchConfig(ctx, logger)
+	if errors.Is(err, ltosapi.ErrEndpointUnavailable) {
+		logger.Debug("Meinberg LTOS device config is unavailable", "error", err)
+		return
+	}
+	if err != nil {
+		logger.Warn("Failed to fetch Meinberg LTOS device config", "error", err)
+		return
+	}
+
+	ch <- configInfo.mustNewConstMetric(1, host, config.Data.System.Timezone, config.Data.PTP.Profile)
+	if config.Data.NTP.Stratum != nil {
+		ch <- configNTPStratum.mustNewConstMetric(float64(*config.Data.NTP.Stratum), host)
+	}
+}
@@ -0,0 +1,11 @@
+package collector
+
+import "testing"
+
+// SetFetchWaitingHook installs hook to be called once a collection waits for a device fetch, for the duration of
+// the test
+func SetFetchWaitingHook(t *testing.T, hook func()) {
+	t.Helper()
+	testHookFetchWaiting = hook
+	t.Cleanup(func() { testHookFetchWaiting = func() {} })
+}
@@ -1,6 +1,10 @@
 package collector
 
 import (
+	"net/url"
+	"strconv"
+	"strings"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/ltosapi/models"
 )
@@ -21,6 +25,24 @@ func boolToFloat64(b bool) float64 {
 	return 0.0
 }
 
+// hostLabel returns the device-reported hostname, falling back to the host of the target URL if it is empty
+func hostLabel(hostname, target string) string {
+	if h := strings.TrimSpace(hostname); h != "" {
+		return h
+	}
+
+	if u, err := url.Parse(target); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+
+	return target
+}
+
+// chassisLabel returns the chassis label value of a slot, the index of its chassis, e.g. "0" for chassis0
+func chassisLabel(slot models.Slot) string {
+	return strconv.Itoa(slot.Chassis)
+}
+
 func forEachSlotWithModule(slots []models.Slot, slotType string, fn func(models.Slot)) {
 	for _, slot := range slots {
 		if slot.Type != slotType || slot.Module == nil {
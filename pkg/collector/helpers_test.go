@@ -60,3 +60,27 @@ func TestBoolToFloat64(t *testing.T) {
 		t.Errorf("boolToFloat64(false) = %v, want 0.0", got)
 	}
 }
+
+func TestHostLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostname string
+		target   string
+		expected string
+	}{
+		{"device hostname", "mbg1.time.example.com", "https://10.0.0.1", "mbg1.time.example.com"},
+		{"whitespace trimmed", "  mbg1  ", "https://10.0.0.1", "mbg1"},
+		{"empty hostname", "", "https://mbg1.time.example.com:8443/", "mbg1.time.example.com"},
+		{"blank hostname", "   ", "http://10.0.0.1", "10.0.0.1"},
+		{"ipv6 target", "", "https://[2001:db8::1]:443", "2001:db8::1"},
+		{"unparsable target", "", "not a url", "not a url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hostLabel(tt.hostname, tt.target); got != tt.expected {
+				t.Errorf("hostLabel(%q, %q) = %q, want %q", tt.hostname, tt.target, got, tt.expected)
+			}
+		})
+	}
+}
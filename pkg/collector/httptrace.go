@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// fetchPhases accumulates the time spent resolving, connecting and in the TLS handshake over all requests of one
+// status fetch. Phases skipped because an idle connection was reused add nothing.
+type fetchPhases struct {
+	mu           sync.Mutex
+	dnsStart     time.Time
+	connectStart map[string]time.Time // by address, as dual-stack dialing may race connections
+	tlsStart     time.Time
+
+	dns          time.Duration
+	connect      time.Duration
+	tlsHandshake time.Duration
+}
+
+func newFetchPhases() *fetchPhases {
+	return &fetchPhases{connectStart: make(map[string]time.Time)}
+}
+
+func (p *fetchPhases) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if !p.dnsStart.IsZero() {
+				p.dns += time.Since(p.dnsStart)
+			}
+		},
+		ConnectStart: func(_, addr string) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.connectStart[addr] = time.Now()
+		},
+		ConnectDone: func(_, addr string, _ error) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if start, ok := p.connectStart[addr]; ok {
+				p.connect += time.Since(start)
+				delete(p.connectStart, addr)
+			}
+		},
+		TLSHandshakeStart: func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if !p.tlsStart.IsZero() {
+				p.tlsHandshake += time.Since(p.tlsStart)
+			}
+		},
+	}
+}
+
+// seconds returns the accumulated DNS, connect and TLS handshake durations in seconds
+func (p *fetchPhases) seconds() (dns, connect, tlsHandshake float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.dns.Seconds(), p.connect.Seconds(), p.tlsHandshake.Seconds()
+}
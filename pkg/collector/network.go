@@ -1,13 +1,36 @@
 package collector
 
 import (
+	"strconv"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/ltosapi/models"
 )
 
-const networkSubsystem = "network_port"
+const (
+	networkSubsystem          = "network_port"
+	networkAggregateSubsystem = "network"
+)
 
 var (
+	networkPorts = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, networkAggregateSubsystem, "ports"),
+			"Number of network ports configured on the device",
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	networkPortsUp = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, networkAggregateSubsystem, "ports_up"),
+			"Number of network ports with link up",
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
 	networkPortUp = typedDesc{
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, networkSubsystem, "up"),
@@ -26,6 +49,24 @@ var (
 		),
 		valueType: prometheus.GaugeValue,
 	}
+	networkPortSpeedBytes = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, networkSubsystem, "speed_bytes"),
+			"Network port link speed in bytes per second",
+			[]string{"host", "port"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	networkInterfaceAddressInfo = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, "network_interface", "address_info"),
+			"Addresses configured on the network interfaces as labels",
+			[]string{"host", "interface", "address_type", "address", "subnet", "assignment"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
 	networkPortRxBytes = typedDesc{
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, networkSubsystem, "rx_bytes_total"),
@@ -100,9 +141,18 @@ var (
 	}
 )
 
-func describeNetwork(ch chan<- *prometheus.Desc) {
+func describeNetwork(ch chan<- *prometheus.Desc, portDetails bool) {
+	ch <- networkPorts.desc
+	ch <- networkPortsUp.desc
+	ch <- networkInterfaceAddressInfo.desc
+
+	if !portDetails {
+		return
+	}
+
 	ch <- networkPortUp.desc
 	ch <- networkPortInfo.desc
+	ch <- networkPortSpeedBytes.desc
 	ch <- networkPortRxBytes.desc
 	ch <- networkPortTxBytes.desc
 	ch <- networkPortRxPackets.desc
@@ -114,6 +164,25 @@ func describeNetwork(ch chan<- *prometheus.Desc) {
 }
 
 func (c *Collector) collectNetwork(ch chan<- prometheus.Metric, host string, network models.Network) {
+	portsUp := 0
+	for _, port := range network.Ports {
+		if port.Link {
+			portsUp++
+		}
+	}
+	ch <- networkPorts.mustNewConstMetric(float64(len(network.Ports)), host)
+	ch <- networkPortsUp.mustNewConstMetric(float64(portsUp), host)
+
+	for _, iface := range network.Interfaces {
+		for _, addr := range iface.Addresses {
+			ch <- networkInterfaceAddressInfo.mustNewConstMetric(1.0, host, iface.Name, addr.Type, addr.Address, addr.Subnet, addr.Assignment)
+		}
+	}
+
+	if !c.config.NetworkPortDetails {
+		return
+	}
+
 	for _, port := range network.Ports {
 		ch <- networkPortUp.mustNewConstMetric(boolToFloat64(port.Link), host, port.Name)
 
@@ -124,6 +193,11 @@ func (c *Collector) collectNetwork(ch chan<- prometheus.Metric, host string, net
 
 		ch <- networkPortInfo.mustNewConstMetric(1.0, host, port.Name, port.Speed, port.Duplex, port.MACAddress, port.CardName)
 
+		// The API reports the link speed in Mbit/s, or "-" if unknown
+		if speedMbits, err := strconv.ParseFloat(port.Speed, 64); err == nil {
+			ch <- networkPortSpeedBytes.mustNewConstMetric(speedMbits*1e6/8, host, port.Name)
+		}
+
 		// Older API versions do not expose network port statistics
 		if port.Statistics == nil {
 			continue
@@ -1,6 +1,8 @@
 package collector
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/ltosapi/models"
 )
@@ -11,7 +13,17 @@ var eventLastTriggered = typedDesc{
 	desc: prometheus.NewDesc(
 		prometheus.BuildFQName(MetricNamespace, notificationSubsystem, "last_triggered_seconds"),
 		"When an event last occurred as seconds since UNIX epoch (0 if never triggered)",
-		[]string{"host", "type", "event"},
+		[]string{"host", "type", "event", "source"},
+		nil,
+	),
+	valueType: prometheus.GaugeValue,
+}
+
+var eventActive = typedDesc{
+	desc: prometheus.NewDesc(
+		prometheus.BuildFQName(MetricNamespace, notificationSubsystem, "event_active"),
+		"Whether an event is currently raised (1 = raised, 0 = cleared)",
+		[]string{"host", "type", "event", "source"},
 		nil,
 	),
 	valueType: prometheus.GaugeValue,
@@ -19,10 +31,21 @@ var eventLastTriggered = typedDesc{
 
 func describeNotification(ch chan<- *prometheus.Desc) {
 	ch <- eventLastTriggered.desc
+	ch <- eventActive.desc
 }
 
 func (c *Collector) collectNotification(ch chan<- prometheus.Metric, host string, events []models.Event) {
+	loc := c.deviceTimezone()
 	for _, event := range events {
-		ch <- eventLastTriggered.mustNewConstMetric(event.LastTriggeredUnix, host, event.Type, event.Name)
+		ch <- eventLastTriggered.mustNewConstMetric(event.LastTriggeredUnixIn(loc), host, event.Type, event.Name, event.Source())
+		ch <- eventActive.mustNewConstMetric(boolToFloat64(event.Active), host, event.Type, event.Name, event.Source())
+	}
+}
+
+// deviceTimezone returns the timezone the device's wall clock timestamps are interpreted in
+func (c *Collector) deviceTimezone() *time.Location {
+	if c.config.DeviceTimezone == nil {
+		return time.UTC
 	}
+	return c.config.DeviceTimezone
 }
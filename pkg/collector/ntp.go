@@ -1,13 +1,20 @@
 package collector
 
 import (
+	"fmt"
+	"log/slog"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/ltosapi/models"
 )
 
 const (
+	ntpSubsystem     = "ntp"
 	ntpSysSubsystem  = "ntp_sys"
 	ntpPeerSubsystem = "ntp_peer"
+
+	// maxNTPPeers bounds the number of peer associations exported per scrape to keep label cardinality in check
+	maxNTPPeers = 64
 )
 
 var (
@@ -16,6 +23,33 @@ var (
 )
 
 var (
+	ntpServiceRunning = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, ntpSubsystem, "service_running"),
+			"Meinberg NTP service running state, independent of synchronization (1 = running, 0 = stopped)",
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	ntpPeersUnreachable = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, ntpSubsystem, "peers_unreachable"),
+			"Number of configured upstream NTP servers that answered none of the last 8 polls (reach = 0), excluding reference clocks",
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	ntpPeersSkipped = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, ntpSubsystem, "peers_skipped"),
+			fmt.Sprintf("Number of NTP peer associations without peer metrics because the device reports more than %d", maxNTPPeers),
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
 	ntpSysStratum = typedDesc{
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, ntpSysSubsystem, "stratum"),
@@ -115,6 +149,15 @@ var (
 		),
 		valueType: prometheus.GaugeValue,
 	}
+	ntpPeerReach = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, ntpPeerSubsystem, "reach"),
+			"Meinberg NTP peer reachability register (8-bit shift register of the last polls, 255 = all reached)",
+			variableLabelsNTPPeers,
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
 	ntpPeerSynchronized = typedDesc{
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, ntpPeerSubsystem, "synchronized"),
@@ -136,6 +179,9 @@ var (
 )
 
 func describeNTP(ch chan<- *prometheus.Desc) {
+	ch <- ntpServiceRunning.desc
+	ch <- ntpPeersUnreachable.desc
+	ch <- ntpPeersSkipped.desc
 	describeNTPSys(ch)
 	describeNTPPeers(ch)
 }
@@ -155,17 +201,43 @@ func describeNTPPeers(ch chan<- *prometheus.Desc) {
 	ch <- ntpPeerOffset.desc
 	ch <- ntpPeerDelay.desc
 	ch <- ntpPeerDispersion.desc
+	ch <- ntpPeerReach.desc
 	ch <- ntpPeerLeapIndicator.desc
 	ch <- ntpPeerSynchronized.desc
 }
 
-func (c *Collector) collectNTP(ch chan<- prometheus.Metric, host string, assocs []models.NTPAssociation) {
+func (c *Collector) collectNTP(ch chan<- prometheus.Metric, host string, assocs []models.NTPAssociation, services models.Services, logger *slog.Logger) {
+	if service, ok := services.Network["ntp"]; ok {
+		ch <- ntpServiceRunning.mustNewConstMetric(boolToFloat64(service.Running), host)
+	}
+
+	peers := 0
+	unreachable := 0
 	for _, a := range assocs {
 		if a.IsSys() {
 			c.collectNTPSysAssoc(ch, host, a)
-		} else {
-			c.collectNTPPeerAssoc(ch, host, a)
+			continue
 		}
+
+		if a.IsUnreachableUpstream() {
+			unreachable++
+		}
+
+		peers++
+		if peers > maxNTPPeers {
+			continue
+		}
+		c.collectNTPPeerAssoc(ch, host, a)
+	}
+
+	skipped := max(peers-maxNTPPeers, 0)
+	if len(assocs) > 0 {
+		ch <- ntpPeersUnreachable.mustNewConstMetric(float64(unreachable), host)
+		ch <- ntpPeersSkipped.mustNewConstMetric(float64(skipped), host)
+	}
+
+	if skipped > 0 {
+		logger.Debug("Too many NTP peer associations, skipping excess peers", "peers", peers, "limit", maxNTPPeers)
 	}
 }
 
@@ -193,6 +265,9 @@ func (c *Collector) collectNTPPeerAssoc(ch chan<- prometheus.Metric, host string
 	if assoc.Dispersion != nil {
 		ch <- ntpPeerDispersion.mustNewConstMetric(*assoc.Dispersion, labels...)
 	}
+	if assoc.Reach != nil {
+		ch <- ntpPeerReach.mustNewConstMetric(*assoc.Reach, labels...)
+	}
 	ch <- ntpPeerLeapIndicator.mustNewConstMetric(float64(assoc.LeapIndicator), labels...)
 	ch <- ntpPeerSynchronized.mustNewConstMetric(boolToFloat64(assoc.LeapIndicator != models.Unknown), labels...)
 }
@@ -12,7 +12,7 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, rcvDCF77Subsystem, "field_strength"),
 			"DCF77 receiver field strength",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -21,7 +21,16 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, rcvDCF77Subsystem, "correlation"),
 			"DCF77 receiver correlation",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	clkReferenceInputLocked = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, "", "reference_input_locked"),
+			"Non-GNSS reference input lock status (1 = locked, 0 = not locked)",
+			[]string{"host", "chassis", "clock_id", "reference_type"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -31,6 +40,7 @@ var (
 func describeReceiverDCF77(ch chan<- *prometheus.Desc) {
 	ch <- clkRcvDCF77FieldStrength.desc
 	ch <- clkRcvDCF77Correlation.desc
+	ch <- clkReferenceInputLocked.desc
 }
 
 func (c *Collector) collectReceiverDCF77(ch chan<- prometheus.Metric, host string, slots []models.Slot) {
@@ -38,7 +48,10 @@ func (c *Collector) collectReceiverDCF77(ch chan<- prometheus.Metric, host strin
 		if slot.Module.DCF77 == nil {
 			return
 		}
-		ch <- clkRcvDCF77FieldStrength.mustNewConstMetric(slot.Module.DCF77.FieldStrength, host, slot.Name)
-		ch <- clkRcvDCF77Correlation.mustNewConstMetric(slot.Module.DCF77.Correlation, host, slot.Name)
+		ch <- clkRcvDCF77FieldStrength.mustNewConstMetric(slot.Module.DCF77.FieldStrength, host, chassisLabel(slot), slot.Name)
+		ch <- clkRcvDCF77Correlation.mustNewConstMetric(slot.Module.DCF77.Correlation, host, chassisLabel(slot), slot.Name)
+		if slot.Module.DCF77.PCPS != nil {
+			ch <- clkReferenceInputLocked.mustNewConstMetric(boolToFloat64(slot.Module.DCF77.PCPS.IsSynchronized), host, chassisLabel(slot), slot.Name, slot.Module.DCF77.Name)
+		}
 	})
 }
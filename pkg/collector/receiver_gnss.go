@@ -1,6 +1,8 @@
 package collector
 
 import (
+	"strconv"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/ltosapi/models"
 )
@@ -12,7 +14,7 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, rcvGNSSSubsystem, "satellites_in_view"),
 			"Number of satellites (theoretically) in view of the GNSS receiver",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -21,7 +23,7 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, rcvGNSSSubsystem, "satellites_good"),
 			"Number of good satellites for the GNSS receiver",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -30,7 +32,7 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, rcvGNSSSubsystem, "latitude_degrees"),
 			"Meinberg GNSS receiver latitude",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -39,7 +41,7 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, rcvGNSSSubsystem, "longitude_degrees"),
 			"Meinberg GNSS receiver longitude",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -48,7 +50,16 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, rcvGNSSSubsystem, "altitude_meters"),
 			"Meinberg GNSS receiver altitude",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	clkRcvGNSSPositionInfo = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, rcvGNSSSubsystem, "position_info"),
+			"Meinberg GNSS receiver position in degrees as labels, e.g. for geomap panels",
+			[]string{"host", "chassis", "clock_id", "latitude", "longitude"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -57,7 +68,7 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, rcvGNSSSubsystem, "antenna_connected"),
 			"Meinberg GNSS receiver antenna connected (1 = connected, 0 = not connected)",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -66,7 +77,16 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, rcvGNSSSubsystem, "antenna_short_circuit"),
 			"Meinberg GNSS receiver antenna short circuit detected (1 = short circuit, 0 = no short circuit)",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	clkRcvGNSSAntLastChange = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, rcvGNSSSubsystem, "antenna_last_change_seconds"),
+			"When an antenna event (e.g. faulty, reconnect, short circuit) last occurred as seconds since UNIX epoch (0 if never)",
+			[]string{"host"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -75,7 +95,7 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, rcvGNSSSubsystem, "synchronized"),
 			"Meinberg GNSS receiver synchronization status (1 = synced, 0 = not synced)",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -84,7 +104,7 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, rcvGNSSSubsystem, "tracking"),
 			"Meinberg GNSS receiver tracking status (1 = tracking, 0 = not tracking)",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -93,7 +113,7 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, rcvGNSSSubsystem, "cold_boot"),
 			"GNSS receiver cold boot status (1 = cold boot, 0 = not cold boot)",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -102,21 +122,25 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, rcvGNSSSubsystem, "warm_boot"),
 			"GNSS receiver warm boot status (1 = warm boot, 0 = not warm boot)",
-			[]string{"host", "clock_id"},
+			[]string{"host", "chassis", "clock_id"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
 	}
 )
 
-func describeReceiverGNSS(ch chan<- *prometheus.Desc) {
+func describeReceiverGNSS(ch chan<- *prometheus.Desc, position bool) {
 	ch <- clkRcvGNSSSatInView.desc
 	ch <- clkRcvGNSSSatGood.desc
-	ch <- clkRcvGNSSLatitude.desc
-	ch <- clkRcvGNSSLongitude.desc
-	ch <- clkRcvGNSSAltitude.desc
+	if position {
+		ch <- clkRcvGNSSLatitude.desc
+		ch <- clkRcvGNSSLongitude.desc
+		ch <- clkRcvGNSSAltitude.desc
+		ch <- clkRcvGNSSPositionInfo.desc
+	}
 	ch <- clkRcvGNSSAntConnected.desc
 	ch <- clkRcvGNSSAntShortCircuit.desc
+	ch <- clkRcvGNSSAntLastChange.desc
 	ch <- clkRcvGNSSSynced.desc
 	ch <- clkRcvGNSSTracking.desc
 	ch <- clkRcvGNSSColdBoot.desc
@@ -126,25 +150,60 @@ func describeReceiverGNSS(ch chan<- *prometheus.Desc) {
 func (c *Collector) collectReceiverGNSS(ch chan<- prometheus.Metric, host string, slots []models.Slot) {
 	forEachClockSlot(slots, func(slot models.Slot) {
 		if slot.Module.Satellites != nil {
-			ch <- clkRcvGNSSSatInView.mustNewConstMetric(slot.Module.Satellites.InView, host, slot.Name)
-			ch <- clkRcvGNSSSatGood.mustNewConstMetric(slot.Module.Satellites.Good, host, slot.Name)
-			ch <- clkRcvGNSSLatitude.mustNewConstMetric(slot.Module.Satellites.Latitude, host, slot.Name)
-			ch <- clkRcvGNSSLongitude.mustNewConstMetric(slot.Module.Satellites.Longitude, host, slot.Name)
-			ch <- clkRcvGNSSAltitude.mustNewConstMetric(slot.Module.Satellites.Altitude, host, slot.Name)
+			ch <- clkRcvGNSSSatInView.mustNewConstMetric(float64(slot.Module.Satellites.InView), host, chassisLabel(slot), slot.Name)
+			ch <- clkRcvGNSSSatGood.mustNewConstMetric(float64(slot.Module.Satellites.Good), host, chassisLabel(slot), slot.Name)
+		}
+
+		if slot.Module.Satellites != nil && c.config.GNSSPosition {
+			ch <- clkRcvGNSSLatitude.mustNewConstMetric(slot.Module.Satellites.Latitude, host, chassisLabel(slot), slot.Name)
+			ch <- clkRcvGNSSLongitude.mustNewConstMetric(slot.Module.Satellites.Longitude, host, chassisLabel(slot), slot.Name)
+			ch <- clkRcvGNSSAltitude.mustNewConstMetric(slot.Module.Satellites.Altitude, host, chassisLabel(slot), slot.Name)
+			ch <- clkRcvGNSSPositionInfo.mustNewConstMetric(1.0, host, chassisLabel(slot), slot.Name,
+				strconv.FormatFloat(slot.Module.Satellites.Latitude, 'f', -1, 64),
+				strconv.FormatFloat(slot.Module.Satellites.Longitude, 'f', -1, 64),
+			)
 		}
 
 		if slot.Module.GRC != nil {
 			if slot.Module.GRC.Antenna != nil {
-				ch <- clkRcvGNSSAntConnected.mustNewConstMetric(boolToFloat64(slot.Module.GRC.Antenna.IsConnected), host, slot.Name)
-				ch <- clkRcvGNSSAntShortCircuit.mustNewConstMetric(boolToFloat64(slot.Module.GRC.Antenna.HasShortCircuit), host, slot.Name)
+				ch <- clkRcvGNSSAntConnected.mustNewConstMetric(boolToFloat64(slot.Module.GRC.Antenna.IsConnected), host, chassisLabel(slot), slot.Name)
+				ch <- clkRcvGNSSAntShortCircuit.mustNewConstMetric(boolToFloat64(slot.Module.GRC.Antenna.HasShortCircuit), host, chassisLabel(slot), slot.Name)
 			}
 
 			if slot.Module.GRC.Receiver != nil {
-				ch <- clkRcvGNSSSynced.mustNewConstMetric(boolToFloat64(slot.Module.GRC.Receiver.IsSynchronized), host, slot.Name)
-				ch <- clkRcvGNSSTracking.mustNewConstMetric(boolToFloat64(slot.Module.GRC.Receiver.IsTracking), host, slot.Name)
-				ch <- clkRcvGNSSWarmBoot.mustNewConstMetric(boolToFloat64(slot.Module.GRC.Receiver.IsWarmBooting), host, slot.Name)
-				ch <- clkRcvGNSSColdBoot.mustNewConstMetric(boolToFloat64(slot.Module.GRC.Receiver.IsColdBooting), host, slot.Name)
+				ch <- clkRcvGNSSSynced.mustNewConstMetric(boolToFloat64(slot.Module.GRC.Receiver.IsSynchronized), host, chassisLabel(slot), slot.Name)
+				ch <- clkRcvGNSSTracking.mustNewConstMetric(boolToFloat64(slot.Module.GRC.Receiver.IsTracking), host, chassisLabel(slot), slot.Name)
+				ch <- clkRcvGNSSWarmBoot.mustNewConstMetric(boolToFloat64(slot.Module.GRC.Receiver.IsWarmBooting), host, chassisLabel(slot), slot.Name)
+				ch <- clkRcvGNSSColdBoot.mustNewConstMetric(boolToFloat64(slot.Module.GRC.Receiver.IsColdBooting), host, chassisLabel(slot), slot.Name)
 			}
 		}
 	})
 }
+
+// collectReceiverGNSSAntennaEvents reports the latest antenna event of devices with a GNSS receiver, as the antenna
+// state is only sampled at scrape time and a flapping connection would otherwise go unnoticed
+func (c *Collector) collectReceiverGNSSAntennaEvents(ch chan<- prometheus.Metric, host string, slots []models.Slot, events []models.Event) {
+	hasGNSS := false
+	forEachClockSlot(slots, func(slot models.Slot) {
+		hasGNSS = hasGNSS || slot.Module.GRC != nil || slot.Module.Satellites != nil
+	})
+	if !hasGNSS {
+		return
+	}
+
+	loc := c.deviceTimezone()
+
+	found := false
+	lastChange := 0.0
+	for _, event := range events {
+		if event.Source() != models.EventSourceAntenna {
+			continue
+		}
+		found = true
+		lastChange = max(lastChange, event.LastTriggeredUnixIn(loc))
+	}
+
+	if found {
+		ch <- clkRcvGNSSAntLastChange.mustNewConstMetric(lastChange, host)
+	}
+}
@@ -1,11 +1,20 @@
 package collector
 
 import (
+	"math"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/ltosapi/models"
 )
 
-const systemSubsystem = "system"
+const (
+	systemSubsystem  = "system"
+	chassisSubsystem = "chassis"
+
+	// slotTypeEmpty is the slot_type label value of chassis slots without a module
+	slotTypeEmpty = "empty"
+)
 
 var (
 	systemInfo = typedDesc{
@@ -21,7 +30,7 @@ var (
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, systemSubsystem, "cpu_info"),
 			"CPU information as labels (model, serial, etc.)",
-			[]string{"host", "model", "serial_number"},
+			[]string{"host", "chassis", "model", "serial_number"},
 			nil,
 		),
 		valueType: prometheus.GaugeValue,
@@ -35,6 +44,24 @@ var (
 		),
 		valueType: prometheus.GaugeValue,
 	}
+	systemBootTimeSeconds = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, systemSubsystem, "boot_time_seconds"),
+			"Boot time of the Meinberg device since unix epoch in seconds, derived from the uptime and the device time (or the exporter's clock if the device time is not reported)",
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	systemPrimaryTimeSource = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, "", "primary_time_source"),
+			"Reference the Meinberg device is currently disciplined by as labels (source: gnss, longwave, ptp, ntp, pps, other, or freerun if not synchronized)",
+			[]string{"host", "source", "reference", "ref_type"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
 	systemCPULoadAvg = typedDesc{
 		desc: prometheus.NewDesc(
 			prometheus.BuildFQName(MetricNamespace, systemSubsystem, "cpu_load_avg"),
@@ -62,27 +89,129 @@ var (
 		),
 		valueType: prometheus.GaugeValue,
 	}
+	systemMemoryCachedBytes = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, systemSubsystem, "memory_cached_bytes"),
+			"Memory used for the page cache in bytes",
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	systemMemoryBuffersBytes = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, systemSubsystem, "memory_buffers_bytes"),
+			"Memory used for buffers in bytes",
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	systemSwapBytes = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, systemSubsystem, "swap_bytes"),
+			"Total swap space in bytes",
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	systemSwapFreeBytes = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, systemSubsystem, "swap_free_bytes"),
+			"Free swap space in bytes",
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	chassisSlots = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, chassisSubsystem, "slots"),
+			"Number of populated chassis slots by slot type (slots without a module are counted as empty)",
+			[]string{"host", "slot_type"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
+	systemConfigPendingChanges = typedDesc{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricNamespace, systemSubsystem, "config_pending_changes"),
+			"Number of configuration changes not yet applied on the device",
+			[]string{"host"},
+			nil,
+		),
+		valueType: prometheus.GaugeValue,
+	}
 )
 
 func describeSystem(ch chan<- *prometheus.Desc) {
 	ch <- systemInfo.desc
 	ch <- systemCPUInfo.desc
 	ch <- systemUptimeSeconds.desc
+	ch <- systemBootTimeSeconds.desc
+	ch <- systemPrimaryTimeSource.desc
 	ch <- systemCPULoadAvg.desc
 	ch <- systemMemoryBytes.desc
 	ch <- systemMemoryFreeBytes.desc
+	ch <- systemMemoryCachedBytes.desc
+	ch <- systemMemoryBuffersBytes.desc
+	ch <- systemSwapBytes.desc
+	ch <- systemSwapFreeBytes.desc
+	ch <- systemConfigPendingChanges.desc
+	ch <- chassisSlots.desc
 }
 
-func (c *Collector) collectSystem(ch chan<- prometheus.Metric, host string, systemInformation models.SystemInformation, system models.System, slots []models.Slot) {
+func (c *Collector) collectSystem(ch chan<- prometheus.Metric, host string, systemInformation models.SystemInformation, system models.System, changes models.Changes, slots []models.Slot) {
 	ch <- systemInfo.mustNewConstMetric(1.0, host, systemInformation.Model, systemInformation.SerialNumber.String())
-	ch <- systemUptimeSeconds.mustNewConstMetric(system.UptimeSeconds, host)
+	ch <- systemUptimeSeconds.mustNewConstMetric(float64(system.UptimeSeconds), host)
+
+	now, ok := system.CurrentTime()
+	if !ok {
+		now = time.Now()
+	}
+	// Rounded to whole seconds so the value does not jitter between scrapes while the device is up
+	bootTime := math.Round(float64(now.UnixMilli())/1000 - float64(system.UptimeSeconds))
+	ch <- systemBootTimeSeconds.mustNewConstMetric(bootTime, host)
+
+	if system.SyncStatus != nil {
+		ch <- systemPrimaryTimeSource.mustNewConstMetric(1.0, host, system.SyncStatus.TimeSource(), system.SyncStatus.Reference, system.SyncStatus.RefType)
+	}
+
 	ch <- systemCPULoadAvg.mustNewConstMetric(system.CPULoad.Load1, host, "1")
 	ch <- systemCPULoadAvg.mustNewConstMetric(system.CPULoad.Load5, host, "5")
 	ch <- systemCPULoadAvg.mustNewConstMetric(system.CPULoad.Load15, host, "15")
 	ch <- systemMemoryBytes.mustNewConstMetric(system.Memory.Total, host)
 	ch <- systemMemoryFreeBytes.mustNewConstMetric(system.Memory.Free, host)
 
+	if system.Memory.Cached != nil {
+		ch <- systemMemoryCachedBytes.mustNewConstMetric(*system.Memory.Cached, host)
+	}
+	if system.Memory.Buffers != nil {
+		ch <- systemMemoryBuffersBytes.mustNewConstMetric(*system.Memory.Buffers, host)
+	}
+	if system.Memory.Swap != nil {
+		ch <- systemSwapBytes.mustNewConstMetric(system.Memory.Swap.Total, host)
+		ch <- systemSwapFreeBytes.mustNewConstMetric(system.Memory.Swap.Free, host)
+	}
+
+	if changes.PendingChanges != nil {
+		ch <- systemConfigPendingChanges.mustNewConstMetric(*changes.PendingChanges, host)
+	}
+
+	slotCounts := make(map[string]int)
+	for _, slot := range slots {
+		if slot.Module == nil {
+			slotCounts[slotTypeEmpty]++
+			continue
+		}
+		slotCounts[slot.Type]++
+	}
+	for slotType, count := range slotCounts {
+		ch <- chassisSlots.mustNewConstMetric(float64(count), host, slotType)
+	}
+
 	forEachCPUSlot(slots, func(slot models.Slot) {
-		ch <- systemCPUInfo.mustNewConstMetric(1.0, host, slot.Module.Info.Model, slot.Module.Info.SerialNumber.String())
+		ch <- systemCPUInfo.mustNewConstMetric(1.0, host, chassisLabel(slot), slot.Module.Info.Model, slot.Module.Info.SerialNumber.String())
 	})
 }
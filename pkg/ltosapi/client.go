@@ -15,25 +15,80 @@
 package ltosapi
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"math"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/ltosapi/models"
 )
 
-const apiStatusPath = "/api/status"
+const (
+	apiStatusPath = "/api/status"
+	apiConfigPath = "/api/config"
+)
+
+// emptyResponseRetryDelay is how long to wait before retrying an empty response, which carries no Retry-After
+const emptyResponseRetryDelay = 500 * time.Millisecond
+
+// DefaultMaxResponseBytes is the default upper bound on the size of an API response body
+const DefaultMaxResponseBytes = 16 << 20
+
+// maxBodySnippetBytes is the number of leading response body bytes included in errors about unexpected content
+const maxBodySnippetBytes = 200
+
+// ErrResponseTooLarge is returned when an API response body exceeds the configured size limit
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// ErrUnmarshalResponse is returned when the API response cannot be decoded into the models
+var ErrUnmarshalResponse = errors.New("failed to unmarshal response")
+
+// ErrUnauthorized is returned when the API rejects the configured credentials with status 401 or 403
+var ErrUnauthorized = errors.New("authentication failed, check the configured credentials")
+
+// ErrRedirect is returned when the API answers with a redirect and following redirects is disabled
+var ErrRedirect = errors.New("redirect not followed")
+
+// ErrUnexpectedContentType is returned when the API answers with something other than JSON, e.g. a login page
+var ErrUnexpectedContentType = errors.New("unexpected content type")
+
+// ErrEndpointUnavailable is returned when an optional endpoint is disabled on the device or forbidden for the user
+var ErrEndpointUnavailable = errors.New("endpoint disabled or forbidden")
+
+// ErrEmptyResponse is returned when the API answers with an empty body, which LTOS devices tend to do while rebooting
+var ErrEmptyResponse = errors.New("empty response body")
 
 // Client represents a Meinberg LTOS API client
 type Client struct {
 	baseURL       url.URL
 	authBasicUser string
 	authBasicPass string
+	bearerToken   string
 	httpClient    *http.Client
+
+	requestTimeout   time.Duration
+	maxResponseBytes int64
+	userAgent        string
+	maxRetries       int
+
+	retries           atomic.Uint64
+	lastStatusCode    atomic.Int64
+	lastResponseBytes atomic.Int64
+	// inFlight bounds the number of concurrent fetches, nil if unlimited
+	inFlight chan struct{}
 }
 
 // Target returns the target base URL of the Meinberg LTOS API client
@@ -41,10 +96,56 @@ func (c *Client) Target() string {
 	return c.baseURL.String()
 }
 
-// NewClient creates a new Meinberg LTOS API client
-func NewClient(baseURL string, authBasicUser, authBasicPass string, ignoreSSLVerify bool) (*Client, error) {
+// NewClient creates a new Meinberg LTOS API client for the given base URL, configured by the given options
+func NewClient(baseURL string, opts ...ClientOption) (*Client, error) {
+	cfg := clientConfig{maxResponseBytes: DefaultMaxResponseBytes, followRedirects: true}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.bearerToken != "" && cfg.authBasicUser != "" {
+		return nil, fmt.Errorf("basic auth and bearer token are mutually exclusive")
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.insecureSkipVerify}
+	if cfg.caFile != "" {
+		caPEM, err := os.ReadFile(cfg.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no valid PEM certificates found in CA file %q", cfg.caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	// Compression is left enabled so the transport requests gzip and transparently decompresses the response
 	transport := http.DefaultTransport.(*http.Transport).Clone()
-	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: ignoreSSLVerify}
+	transport.TLSClientConfig = tlsConfig
+
+	if cfg.maxIdleConns != nil {
+		transport.MaxIdleConns = *cfg.maxIdleConns
+		transport.MaxIdleConnsPerHost = *cfg.maxIdleConns
+		if *cfg.maxIdleConns == 0 {
+			// A MaxIdleConnsPerHost of 0 means http.DefaultMaxIdleConnsPerHost rather than no limit
+			transport.MaxIdleConnsPerHost = math.MaxInt
+		}
+	}
+	if cfg.idleConnTimeout != nil {
+		transport.IdleConnTimeout = *cfg.idleConnTimeout
+	}
+
+	if cfg.proxyURL != "" {
+		parsedProxyURL, err := url.Parse(cfg.proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		if parsedProxyURL.Scheme == "" || parsedProxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL: must include URL scheme and host")
+		}
+		transport.Proxy = http.ProxyURL(parsedProxyURL)
+	}
 
 	parsedURL, err := url.Parse(baseURL)
 	if err != nil {
@@ -54,13 +155,42 @@ func NewClient(baseURL string, authBasicUser, authBasicPass string, ignoreSSLVer
 		return nil, fmt.Errorf("invalid base URL: must include URL scheme and host")
 	}
 
+	var rt http.RoundTripper = transport
+	if cfg.transport != nil {
+		rt = cfg.transport
+	}
+	if cfg.transportWrapper != nil {
+		rt = cfg.transportWrapper(rt)
+	}
+
+	// A redirect to a login page usually means an auth proxy rejected the request, so it is reported instead of
+	// parsing whatever the redirect target returns
+	var checkRedirect func(*http.Request, []*http.Request) error
+	if !cfg.followRedirects {
+		checkRedirect = func(req *http.Request, _ []*http.Request) error {
+			return fmt.Errorf("%w: device redirected to %s", ErrRedirect, req.URL.Redacted())
+		}
+	}
+
+	var inFlight chan struct{}
+	if cfg.maxConcurrentFetches > 0 {
+		inFlight = make(chan struct{}, cfg.maxConcurrentFetches)
+	}
+
 	return &Client{
 		baseURL:       *parsedURL,
-		authBasicUser: authBasicUser,
-		authBasicPass: authBasicPass,
+		authBasicUser: cfg.authBasicUser,
+		authBasicPass: cfg.authBasicPass,
+		bearerToken:   cfg.bearerToken,
 		httpClient: &http.Client{
-			Transport: transport,
+			Transport:     rt,
+			CheckRedirect: checkRedirect,
 		},
+		requestTimeout:   cfg.timeout,
+		inFlight:         inFlight,
+		maxResponseBytes: cfg.maxResponseBytes,
+		userAgent:        cfg.userAgent,
+		maxRetries:       cfg.maxRetries,
 	}, nil
 }
 
@@ -69,37 +199,296 @@ func (c *Client) FetchStatus(ctx context.Context, logger *slog.Logger) (*models.
 	url := c.baseURL.JoinPath(apiStatusPath).String()
 	logger = logger.With("url", url)
 
-	logger.Debug("Fetching status from Meinberg LTOS device API")
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	body, statusCode, err := c.fetch(ctx, url, logger)
+	// Kept at 0 unless a response was received and its body was read, respectively
+	c.lastStatusCode.Store(int64(statusCode))
+	c.lastResponseBytes.Store(int64(len(body)))
 	if err != nil {
+		warnStatusCode(logger, statusCode, err)
 		return nil, err
 	}
 
-	if c.authBasicUser != "" && c.authBasicPass != "" {
-		req.SetBasicAuth(c.authBasicUser, c.authBasicPass)
+	var data models.StatusResponse
+	if err := decodeJSONObject(body, &data); err != nil {
+		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	logger.Debug("Successfully fetched status from Meinberg LTOS device API")
+	return &data, nil
+}
+
+// FetchConfig fetches the target configuration from the Meinberg LTOS API. Devices that disable the endpoint or
+// forbid it for the configured user answer with status 404 or 403, which yields ErrEndpointUnavailable.
+func (c *Client) FetchConfig(ctx context.Context, logger *slog.Logger) (*models.ConfigResponse, error) {
+	url := c.baseURL.JoinPath(apiConfigPath).String()
+	logger = logger.With("url", url)
+
+	body, statusCode, err := c.fetch(ctx, url, logger)
+	if statusCode == http.StatusForbidden || statusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w (status code %d)", ErrEndpointUnavailable, statusCode)
+	}
 	if err != nil {
+		warnStatusCode(logger, statusCode, err)
 		return nil, err
 	}
+
+	var data models.ConfigResponse
+	if err := decodeJSONObject(body, &data); err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Successfully fetched config from Meinberg LTOS device API")
+	return &data, nil
+}
+
+// fetch sends a GET request to url and returns the response body along with the status code, which is 0 if no
+// response was received. The body is nil unless it was read. Error status codes are left to the caller to log, as
+// some are expected for optional endpoints.
+func (c *Client) fetch(ctx context.Context, url string, logger *slog.Logger) ([]byte, int, error) {
+	logger.Debug("Fetching from Meinberg LTOS device API")
+
+	// The deadline lives on the context rather than the http.Client, so each fetch gets its own budget that also
+	// covers reading the body, bounded by the deadline of the caller
+	if c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+
+	if c.inFlight != nil {
+		select {
+		case c.inFlight <- struct{}{}:
+			defer func() { <-c.inFlight }()
+		case <-ctx.Done():
+			return nil, 0, fmt.Errorf("waiting for a concurrent fetch to finish: %w", ctx.Err())
+		}
+	}
+
+	resp, err := c.doWithRetry(ctx, url, logger)
+	if err != nil {
+		return nil, 0, err
+	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
 			logger.Warn("Failed to close response body", "error", err)
 		}
 	}()
 
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, resp.StatusCode, fmt.Errorf("%w (status code %d)", ErrUnauthorized, resp.StatusCode)
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		logger.Warn("Unexpected status code from Meinberg LTOS device API", "status_code", resp.StatusCode)
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, resp.StatusCode, &StatusError{StatusCode: resp.StatusCode}
 	}
 
-	var data models.StatusResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal status response: %w", err)
+	body, err := c.readBody(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
 	}
 
-	logger.Debug("Successfully fetched status from Meinberg LTOS device API")
-	return &data, nil
+	if len(bytes.TrimSpace(body)) == 0 {
+		logger.Warn("Empty response body from Meinberg LTOS device API")
+		return body, resp.StatusCode, ErrEmptyResponse
+	}
+
+	// Auth proxies and login redirects tend to answer with an HTML page and status 200. Responses without a
+	// Content-Type header are still attempted as JSON.
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && !strings.Contains(contentType, "application/json") {
+		logger.Warn("Unexpected content type from Meinberg LTOS device API", "content_type", contentType)
+		return body, resp.StatusCode, fmt.Errorf("%w %q, response starts with: %q", ErrUnexpectedContentType, contentType, bodySnippet(body))
+	}
+
+	return body, resp.StatusCode, nil
+}
+
+// warnStatusCode logs a warning if err is about an error status code returned by fetch
+func warnStatusCode(logger *slog.Logger, statusCode int, err error) {
+	var statusErr *StatusError
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		logger.Warn("Authentication failed at Meinberg LTOS device API", "status_code", statusCode)
+	case errors.As(err, &statusErr):
+		logger.Warn("Unexpected status code from Meinberg LTOS device API", "status_code", statusCode)
+	}
+}
+
+// decodeJSONObject unmarshals body into v, which must be a pointer to a struct
+func decodeJSONObject(body []byte, v any) error {
+	// Anything but an object would either fail with a generic type error or, for null, silently yield an empty struct.
+	// Malformed JSON is left to json.Unmarshal, whose error points at the offending byte.
+	if topLevel, ok := jsonTopLevelType(body); ok && topLevel != "object" {
+		return fmt.Errorf("%w: expected a JSON object at the top level, got %s, response starts with: %q", ErrUnmarshalResponse, topLevel, bodySnippet(body))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshalResponse, err)
+	}
+
+	return nil
+}
+
+// doWithRetry sends a GET request to url. Responses with status 429 or 503 and a Retry-After header are retried up to
+// maxRetries times after waiting for the requested delay. Empty 200 responses, as sent by devices mid-reboot, count
+// against the same maxRetries and are retried after emptyResponseRetryDelay.
+func (c *Client) doWithRetry(ctx context.Context, url string, logger *slog.Logger) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.do(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+
+		if attempt >= c.maxRetries {
+			return resp, nil
+		}
+
+		var delay time.Duration
+		switch resp.StatusCode {
+		case http.StatusOK:
+			// The body is buffered to tell an empty response from a valid one, and handed back for decoding otherwise
+			body, err := c.readBody(resp.Body)
+			if closeErr := resp.Body.Close(); closeErr != nil {
+				logger.Warn("Failed to close response body", "error", closeErr)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if len(bytes.TrimSpace(body)) != 0 {
+				resp.Body = io.NopCloser(bytes.NewReader(body))
+				return resp, nil
+			}
+			delay = emptyResponseRetryDelay
+			logger.Info("Meinberg LTOS device API sent an empty response, retrying", "retry_after", delay, "attempt", attempt+1)
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			var ok bool
+			delay, ok = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			if !ok {
+				return resp, nil
+			}
+			if err := resp.Body.Close(); err != nil {
+				logger.Warn("Failed to close response body", "error", err)
+			}
+			logger.Info("Meinberg LTOS device API asked to retry later", "status_code", resp.StatusCode, "retry_after", delay, "attempt", attempt+1)
+		default:
+			return resp, nil
+		}
+
+		c.retries.Add(1)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// do sends a single GET request to url with the configured authentication and User-Agent
+func (c *Client) do(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.authBasicUser != "" && c.authBasicPass != "" {
+		req.SetBasicAuth(c.authBasicUser, c.authBasicPass)
+	}
+
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// Retries returns the number of requests retried because the device asked to retry later or sent an empty response
+func (c *Client) Retries() uint64 {
+	return c.retries.Load()
+}
+
+// LastResponseBytes returns the size of the decompressed body of the last status response read, 0 if it was not read
+// because the request failed or the device answered with an error status
+func (c *Client) LastResponseBytes() int64 {
+	return c.lastResponseBytes.Load()
+}
+
+// LastStatusCode returns the HTTP status code of the response to the last status fetch, 0 if it got no response
+func (c *Client) LastStatusCode() int {
+	return int(c.lastStatusCode.Load())
+}
+
+// parseRetryAfter parses a Retry-After header given either as delay in seconds or as HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// readBody reads the response body, enforcing the configured size limit
+func (c *Client) readBody(r io.Reader) ([]byte, error) {
+	if c.maxResponseBytes <= 0 {
+		return io.ReadAll(r)
+	}
+
+	// Read one byte past the limit to tell a body of exactly maxResponseBytes from an oversized one
+	body, err := io.ReadAll(io.LimitReader(r, c.maxResponseBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(body)) > c.maxResponseBytes {
+		return nil, fmt.Errorf("%w: exceeds limit of %d bytes", ErrResponseTooLarge, c.maxResponseBytes)
+	}
+
+	return body, nil
+}
+
+// jsonTopLevelType names the type of the top-level JSON value of body, e.g. "object" or "array", from its first
+// token. It returns false if body does not start with a JSON value.
+func jsonTopLevelType(body []byte) (string, bool) {
+	tok, err := json.NewDecoder(bytes.NewReader(body)).Token()
+	if err != nil {
+		return "", false
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			return "object", true
+		}
+		return "array", true
+	case string:
+		return "string", true
+	case float64:
+		return "number", true
+	case bool:
+		return "boolean", true
+	case nil:
+		return "null", true
+	default:
+		return fmt.Sprintf("%T", v), true
+	}
+}
+
+// bodySnippet returns the leading bytes of a response body for use in error messages
+func bodySnippet(body []byte) string {
+	if len(body) > maxBodySnippetBytes {
+		return string(body[:maxBodySnippetBytes]) + "..."
+	}
+	return string(body)
 }
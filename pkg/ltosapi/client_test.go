@@ -1,10 +1,20 @@
 package ltosapi
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/pem"
+	"errors"
+	"io"
 	"log/slog"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -21,7 +31,7 @@ func testLogger() *slog.Logger {
 }
 
 func TestTarget(t *testing.T) {
-	client, err := NewClient("https://clock.example.com", "", "", false)
+	client, err := NewClient("https://clock.example.com")
 	if err != nil {
 		t.Errorf("unexpected error calling NewClient()")
 	}
@@ -33,12 +43,12 @@ func TestTarget(t *testing.T) {
 func TestInvalidTarget(t *testing.T) {
 	var err error
 
-	_, err = NewClient("", "", "", false)
+	_, err = NewClient("")
 	if err == nil {
 		t.Errorf("expected error, got nil for empty baseURL")
 	}
 
-	_, err = NewClient("foobar", "", "", false)
+	_, err = NewClient("foobar")
 	if err == nil {
 		t.Errorf("expected error, got nil for baseURL 'foobar'")
 	}
@@ -60,7 +70,7 @@ func TestFetchStatus_Success(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	client, _ := NewClient(srv.URL, "", "", false)
+	client, _ := NewClient(srv.URL)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -76,18 +86,46 @@ func TestFetchStatus_Success(t *testing.T) {
 	}
 }
 
+func TestFetchStatus_GzipEncoded(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept-Encoding"); got != "gzip" {
+			t.Errorf("Accept-Encoding = %q, want %q", got, "gzip")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		if _, err := gz.Write([]byte(`{"system-information": {"hostname": "clock1"}, "data": {"rest-api": {}}}`)); err != nil {
+			t.Errorf("failed to write gzip response: %v", err)
+		}
+		if err := gz.Close(); err != nil {
+			t.Errorf("failed to close gzip writer: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	client, _ := NewClient(srv.URL)
+	status, err := client.FetchStatus(context.Background(), testLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.SystemInformation.Hostname != "clock1" {
+		t.Errorf("hostname = %q, want %q", status.SystemInformation.Hostname, "clock1")
+	}
+}
+
 func TestFetchStatus_BasicAuth(t *testing.T) {
 	var gotUser, gotPass string
 	var authPresent bool
 
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		gotUser, gotPass, authPresent = r.BasicAuth()
+		w.Header().Set("Content-Type", "application/json")
 		mustWrite(t, w, []byte(`{"system-information": {}, "data": {"rest-api": {}}}`))
 	}))
 	defer srv.Close()
 
 	t.Run("credentials sent when configured", func(t *testing.T) {
-		client, _ := NewClient(srv.URL, "myuser", "mypass", false)
+		client, _ := NewClient(srv.URL, WithBasicAuth("myuser", "mypass"))
 		_, err := client.FetchStatus(context.Background(), testLogger())
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -101,7 +139,7 @@ func TestFetchStatus_BasicAuth(t *testing.T) {
 	})
 
 	t.Run("no auth header when credentials empty", func(t *testing.T) {
-		client, _ := NewClient(srv.URL, "", "", false)
+		client, _ := NewClient(srv.URL)
 		_, err := client.FetchStatus(context.Background(), testLogger())
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -112,6 +150,37 @@ func TestFetchStatus_BasicAuth(t *testing.T) {
 	})
 }
 
+func TestFetchStatus_UserAgent(t *testing.T) {
+	var gotUserAgent string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.UserAgent()
+		w.Header().Set("Content-Type", "application/json")
+		mustWrite(t, w, []byte(`{"system-information": {}, "data": {"rest-api": {}}}`))
+	}))
+	defer srv.Close()
+
+	t.Run("configured user agent is sent", func(t *testing.T) {
+		client, _ := NewClient(srv.URL, WithUserAgent("meinberg_ltos_exporter/1.2.3"))
+		if _, err := client.FetchStatus(context.Background(), testLogger()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotUserAgent != "meinberg_ltos_exporter/1.2.3" {
+			t.Errorf("User-Agent = %q, want %q", gotUserAgent, "meinberg_ltos_exporter/1.2.3")
+		}
+	})
+
+	t.Run("default user agent when empty", func(t *testing.T) {
+		client, _ := NewClient(srv.URL)
+		if _, err := client.FetchStatus(context.Background(), testLogger()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(gotUserAgent, "Go-http-client/") {
+			t.Errorf("User-Agent = %q, want Go default", gotUserAgent)
+		}
+	})
+}
+
 func TestFetchStatus_Non200Status(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -130,7 +199,7 @@ func TestFetchStatus_Non200Status(t *testing.T) {
 			}))
 			defer srv.Close()
 
-			client, _ := NewClient(srv.URL, "", "", false)
+			client, _ := NewClient(srv.URL)
 			status, err := client.FetchStatus(context.Background(), testLogger())
 			if err == nil {
 				t.Fatal("expected error for non-200 status code")
@@ -138,21 +207,146 @@ func TestFetchStatus_Non200Status(t *testing.T) {
 			if status != nil {
 				t.Error("expected nil status on error")
 			}
+			if got := client.LastStatusCode(); got != tt.statusCode {
+				t.Errorf("LastStatusCode() = %d, want %d", got, tt.statusCode)
+			}
+			if got := client.LastResponseBytes(); got != 0 {
+				t.Errorf("LastResponseBytes() = %d, want 0", got)
+			}
 		})
 	}
 }
 
 func TestFetchStatus_InvalidJSON(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
 		mustWrite(t, w, []byte(`not valid json`))
 	}))
 	defer srv.Close()
 
-	client, _ := NewClient(srv.URL, "", "", false)
+	client, _ := NewClient(srv.URL)
 	_, err := client.FetchStatus(context.Background(), testLogger())
 	if err == nil {
 		t.Fatal("expected error for invalid JSON response")
 	}
+	if got := client.LastStatusCode(); got != http.StatusOK {
+		t.Errorf("LastStatusCode() = %d, want %d", got, http.StatusOK)
+	}
+	if got := client.LastResponseBytes(); got != int64(len("not valid json")) {
+		t.Errorf("LastResponseBytes() = %d, want %d", got, len("not valid json"))
+	}
+}
+
+func TestFetchStatus_MaxResponseBytes(t *testing.T) {
+	payload := []byte(`{"system-information": {"hostname": "clock1"}, "data": {"rest-api": {}}}`)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		mustWrite(t, w, payload)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		limit     int64
+		expectErr bool
+	}{
+		{"no limit", 0, false},
+		{"limit equals body size", int64(len(payload)), false},
+		{"limit below body size", int64(len(payload)) - 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := NewClient(srv.URL, WithMaxResponseBytes(tt.limit))
+			_, err := client.FetchStatus(context.Background(), testLogger())
+			if tt.expectErr {
+				if !errors.Is(err, ErrResponseTooLarge) {
+					t.Fatalf("error = %v, want ErrResponseTooLarge", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFetchStatus_ContentType(t *testing.T) {
+	loginPage := "<!DOCTYPE html><html><head><title>Login</title></head><body>" + strings.Repeat("x", 300) + "</body></html>"
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		expectErr   bool
+	}{
+		{"json", "application/json", `{"system-information": {}, "data": {"rest-api": {}}}`, false},
+		{"json with charset", "application/json; charset=utf-8", `{"system-information": {}, "data": {"rest-api": {}}}`, false},
+		{"html login page", "text/html; charset=utf-8", loginPage, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				mustWrite(t, w, []byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client, _ := NewClient(srv.URL)
+			_, err := client.FetchStatus(context.Background(), testLogger())
+			if !tt.expectErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error for non-JSON content type")
+			}
+			if !strings.Contains(err.Error(), "<title>Login</title>") {
+				t.Errorf("error %q does not include the start of the response body", err)
+			}
+			if strings.Contains(err.Error(), "</html>") {
+				t.Errorf("error %q includes more than the start of the response body", err)
+			}
+		})
+	}
+}
+
+func TestFetchStatus_TopLevelNotObject(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"array", `[{"data": {}}]`, "got array"},
+		{"string", `"maintenance"`, "got string"},
+		{"number", `42`, "got number"},
+		{"boolean", `true`, "got boolean"},
+		{"null", `null`, "got null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				mustWrite(t, w, []byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client, _ := NewClient(srv.URL)
+			_, err := client.FetchStatus(context.Background(), testLogger())
+			if !errors.Is(err, ErrUnmarshalResponse) {
+				t.Fatalf("expected ErrUnmarshalResponse, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error to contain %q, got %v", tt.expected, err)
+			}
+		})
+	}
 }
 
 func TestFetchStatus_ConnectionRefused(t *testing.T) {
@@ -161,7 +355,7 @@ func TestFetchStatus_ConnectionRefused(t *testing.T) {
 	url := srv.URL
 	srv.Close()
 
-	client, _ := NewClient(url, "", "", false)
+	client, _ := NewClient(url)
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
@@ -169,6 +363,9 @@ func TestFetchStatus_ConnectionRefused(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error when server is unreachable")
 	}
+	if got := client.LastStatusCode(); got != 0 {
+		t.Errorf("LastStatusCode() = %d, want 0", got)
+	}
 }
 
 func TestFetchStatus_ContextCancelled(t *testing.T) {
@@ -178,7 +375,7 @@ func TestFetchStatus_ContextCancelled(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	client, _ := NewClient(srv.URL, "", "", false)
+	client, _ := NewClient(srv.URL)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // cancel immediately
@@ -190,7 +387,7 @@ func TestFetchStatus_ContextCancelled(t *testing.T) {
 }
 
 func TestNewClient_ClonesDefaultTransport(t *testing.T) {
-	client, _ := NewClient("https://clock.example.com", "", "", true)
+	client, _ := NewClient("https://clock.example.com", WithInsecureSkipVerify(true))
 
 	transport, ok := client.httpClient.Transport.(*http.Transport)
 	if !ok {
@@ -218,7 +415,7 @@ func TestNewClient_ClonesDefaultTransport(t *testing.T) {
 }
 
 func TestNewClient_DisablesInsecureSkipVerifyWhenRequested(t *testing.T) {
-	client, _ := NewClient("https://clock.example.com", "", "", false)
+	client, _ := NewClient("https://clock.example.com")
 
 	transport, ok := client.httpClient.Transport.(*http.Transport)
 	if !ok {
@@ -232,3 +429,535 @@ func TestNewClient_DisablesInsecureSkipVerifyWhenRequested(t *testing.T) {
 		t.Fatal("expected InsecureSkipVerify=false")
 	}
 }
+
+func TestNewClient_ProxyURL(t *testing.T) {
+	client, err := NewClient("https://clock.example.com", WithProxyURL("http://proxy.example.com:3128"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport type = %T, want *http.Transport", client.httpClient.Transport)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://clock.example.com/api/status", nil)
+	proxy, err := transport.Proxy(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if proxy == nil || proxy.String() != "http://proxy.example.com:3128" {
+		t.Errorf("proxy = %v, want http://proxy.example.com:3128", proxy)
+	}
+}
+
+func TestNewClient_InvalidProxyURL(t *testing.T) {
+	for _, proxyURL := range []string{"proxy.example.com", "://broken"} {
+		if _, err := NewClient("https://clock.example.com", WithProxyURL(proxyURL)); err == nil {
+			t.Errorf("expected error, got nil for proxy URL %q", proxyURL)
+		}
+	}
+}
+
+func TestFetchStatus_BearerToken(t *testing.T) {
+	var gotAuth string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		mustWrite(t, w, []byte(`{"system-information": {}, "data": {"rest-api": {}}}`))
+	}))
+	defer srv.Close()
+
+	client, _ := NewClient(srv.URL, WithBearerToken("secret-token"))
+	if _, err := client.FetchStatus(context.Background(), testLogger()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAuth != "Bearer secret-token" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "Bearer secret-token")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestFetchStatus_Transport(t *testing.T) {
+	var gotURL string
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		gotURL = r.URL.String()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"system-information": {"hostname": "mbg1"}, "data": {"rest-api": {}}}`)),
+			Request:    r,
+		}, nil
+	})
+
+	client, _ := NewClient("https://clock.example.com", WithTransport(rt))
+	status, err := client.FetchStatus(context.Background(), testLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotURL != "https://clock.example.com/api/status" {
+		t.Errorf("request URL = %q, want %q", gotURL, "https://clock.example.com/api/status")
+	}
+	if status.SystemInformation.Hostname != "mbg1" {
+		t.Errorf("hostname = %q, want %q", status.SystemInformation.Hostname, "mbg1")
+	}
+}
+
+func TestFetchStatus_TransportWrapper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		mustWrite(t, w, []byte(`{"system-information": {}, "data": {"rest-api": {}}}`))
+	}))
+	defer srv.Close()
+
+	var wrapped atomic.Int32
+	client, _ := NewClient(srv.URL, WithTransportWrapper(func(rt http.RoundTripper) http.RoundTripper {
+		if _, ok := rt.(*http.Transport); !ok {
+			t.Errorf("wrapped transport type = %T, want *http.Transport", rt)
+		}
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			wrapped.Add(1)
+			return rt.RoundTrip(r)
+		})
+	}))
+	if _, err := client.FetchStatus(context.Background(), testLogger()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := wrapped.Load(); got != 1 {
+		t.Errorf("requests through wrapper = %d, want 1", got)
+	}
+}
+
+func TestFetchStatus_Redirect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			w.Header().Set("Content-Type", "application/json")
+			mustWrite(t, w, []byte(`{"system-information": {"hostname": "mbg1"}, "data": {"rest-api": {}}}`))
+			return
+		}
+		http.Redirect(w, r, "/login", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	t.Run("followed by default", func(t *testing.T) {
+		client, _ := NewClient(srv.URL)
+		if _, err := client.FetchStatus(context.Background(), testLogger()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		client, _ := NewClient(srv.URL, WithFollowRedirects(false))
+		_, err := client.FetchStatus(context.Background(), testLogger())
+		if !errors.Is(err, ErrRedirect) {
+			t.Fatalf("expected ErrRedirect, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "/login") {
+			t.Errorf("error %q does not name the redirect target", err)
+		}
+	})
+}
+
+func TestFetchStatus_MaxConcurrentFetches(t *testing.T) {
+	var active, maxActive atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			m := maxActive.Load()
+			if n <= m || maxActive.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		mustWrite(t, w, []byte(`{"system-information": {}, "data": {"rest-api": {}}}`))
+	}))
+	defer srv.Close()
+
+	client, _ := NewClient(srv.URL, WithMaxConcurrentFetches(1))
+
+	const fetches = 4
+	errs := make(chan error, fetches)
+	for range fetches {
+		go func() {
+			_, err := client.FetchStatus(context.Background(), testLogger())
+			errs <- err
+		}()
+	}
+	for range fetches {
+		if err := <-errs; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := maxActive.Load(); got != 1 {
+		t.Errorf("max concurrent requests = %d, want 1", got)
+	}
+}
+
+func TestFetchStatus_MaxConcurrentFetchesContextDone(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		mustWrite(t, w, []byte(`{"system-information": {}, "data": {"rest-api": {}}}`))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client, _ := NewClient(srv.URL, WithMaxConcurrentFetches(1))
+	go func() { _, _ = client.FetchStatus(context.Background(), testLogger()) }()
+	for len(client.inFlight) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.FetchStatus(ctx, testLogger()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded while waiting, got %v", err)
+	}
+}
+
+func TestNewClient_BasicAuthAndBearerTokenExclusive(t *testing.T) {
+	if _, err := NewClient("https://clock.example.com", WithBasicAuth("user", "pass"), WithBearerToken("token")); err == nil {
+		t.Fatal("expected error when combining basic auth and bearer token")
+	}
+}
+
+func TestFetchStatus_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		// Stall while sending the body, after the headers were already received
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	client, _ := NewClient(srv.URL, WithTimeout(50*time.Millisecond))
+	if client.httpClient.Timeout != 0 {
+		t.Errorf("http.Client timeout = %v, want 0 as the deadline is set per fetch", client.httpClient.Timeout)
+	}
+
+	start := time.Now()
+	_, err := client.FetchStatus(context.Background(), testLogger())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("fetch took %v, want it bounded by the timeout", elapsed)
+	}
+}
+
+func TestNewClient_DefaultMaxResponseBytes(t *testing.T) {
+	client, _ := NewClient("https://clock.example.com")
+	if client.maxResponseBytes != DefaultMaxResponseBytes {
+		t.Errorf("maxResponseBytes = %d, want %d", client.maxResponseBytes, DefaultMaxResponseBytes)
+	}
+}
+
+func TestFetchStatus_CAFile(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		mustWrite(t, w, []byte(`{"system-information": {}, "data": {"rest-api": {}}}`))
+	}))
+	defer srv.Close()
+
+	t.Run("untrusted certificate rejected", func(t *testing.T) {
+		client, _ := NewClient(srv.URL)
+		if _, err := client.FetchStatus(context.Background(), testLogger()); err == nil {
+			t.Fatal("expected certificate verification error")
+		}
+	})
+
+	t.Run("certificate trusted via CA file", func(t *testing.T) {
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+		if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		client, err := NewClient(srv.URL, WithCAFile(caFile))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := client.FetchStatus(context.Background(), testLogger()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("invalid CA file", func(t *testing.T) {
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		if err := os.WriteFile(caFile, []byte("not a certificate"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewClient(srv.URL, WithCAFile(caFile)); err == nil {
+			t.Fatal("expected error for invalid CA file")
+		}
+		if _, err := NewClient(srv.URL, WithCAFile(filepath.Join(t.TempDir(), "missing.pem"))); err == nil {
+			t.Fatal("expected error for missing CA file")
+		}
+	})
+}
+
+func TestFetchStatus_EmptyBody(t *testing.T) {
+	for _, body := range []string{"", "  \n\t "} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			mustWrite(t, w, []byte(body))
+		}))
+
+		client, _ := NewClient(srv.URL)
+		_, err := client.FetchStatus(context.Background(), testLogger())
+		if !errors.Is(err, ErrEmptyResponse) {
+			t.Errorf("error = %v, want ErrEmptyResponse for body %q", err, body)
+		}
+
+		srv.Close()
+	}
+}
+
+func TestFetchStatus_RetryAfter(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		mustWrite(t, w, []byte(`{"system-information": {"hostname": "clock1"}, "data": {"rest-api": {}}}`))
+	}))
+	defer srv.Close()
+
+	t.Run("retried when enabled", func(t *testing.T) {
+		requests.Store(0)
+		client, _ := NewClient(srv.URL, WithMaxRetries(1))
+		if _, err := client.FetchStatus(context.Background(), testLogger()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := requests.Load(); got != 2 {
+			t.Errorf("requests = %d, want 2", got)
+		}
+		if got := client.Retries(); got != 1 {
+			t.Errorf("Retries() = %d, want 1", got)
+		}
+	})
+
+	t.Run("not retried by default", func(t *testing.T) {
+		requests.Store(0)
+		client, _ := NewClient(srv.URL)
+		if _, err := client.FetchStatus(context.Background(), testLogger()); err == nil {
+			t.Fatal("expected error for status 503")
+		}
+		if got := client.Retries(); got != 0 {
+			t.Errorf("Retries() = %d, want 0", got)
+		}
+	})
+}
+
+func TestFetchStatus_RetryEmptyBody(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if requests.Add(1) == 1 {
+			return
+		}
+		mustWrite(t, w, []byte(`{"system-information": {"hostname": "clock1"}, "data": {"rest-api": {}}}`))
+	}))
+	defer srv.Close()
+
+	t.Run("retried when enabled", func(t *testing.T) {
+		requests.Store(0)
+		client, _ := NewClient(srv.URL, WithMaxRetries(1))
+		status, err := client.FetchStatus(context.Background(), testLogger())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status.SystemInformation.Hostname != "clock1" {
+			t.Errorf("hostname = %q, want clock1", status.SystemInformation.Hostname)
+		}
+		if got := requests.Load(); got != 2 {
+			t.Errorf("requests = %d, want 2", got)
+		}
+		if got := client.Retries(); got != 1 {
+			t.Errorf("Retries() = %d, want 1", got)
+		}
+	})
+
+	t.Run("not retried by default", func(t *testing.T) {
+		requests.Store(0)
+		client, _ := NewClient(srv.URL)
+		if _, err := client.FetchStatus(context.Background(), testLogger()); !errors.Is(err, ErrEmptyResponse) {
+			t.Fatalf("error = %v, want ErrEmptyResponse", err)
+		}
+		if got := requests.Load(); got != 1 {
+			t.Errorf("requests = %d, want 1", got)
+		}
+	})
+
+	t.Run("bounded by the fetch deadline", func(t *testing.T) {
+		requests.Store(0)
+		client, _ := NewClient(srv.URL, WithMaxRetries(1), WithTimeout(emptyResponseRetryDelay/10))
+		if _, err := client.FetchStatus(context.Background(), testLogger()); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+		}
+		if got := requests.Load(); got != 1 {
+			t.Errorf("requests = %d, want 1", got)
+		}
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"seconds", "5", 5 * time.Second, true},
+		{"zero", "0", 0, true},
+		{"negative", "-3", 0, true},
+		{"http date", "Tue, 10 Feb 2026 12:00:30 GMT", 30 * time.Second, true},
+		{"http date in the past", "Tue, 10 Feb 2026 11:59:00 GMT", 0, true},
+		{"empty", "", 0, false},
+		{"invalid", "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.ok || got != tt.expected {
+				t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNewClient_IdleConns(t *testing.T) {
+	client, _ := NewClient("https://clock.example.com", WithMaxIdleConns(1), WithIdleConnTimeout(30*time.Second))
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport type = %T, want *http.Transport", client.httpClient.Transport)
+	}
+
+	if transport.MaxIdleConns != 1 || transport.MaxIdleConnsPerHost != 1 {
+		t.Errorf("MaxIdleConns = %d, MaxIdleConnsPerHost = %d, want 1", transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != 30*time.Second {
+		t.Errorf("IdleConnTimeout = %v, want %v", transport.IdleConnTimeout, 30*time.Second)
+	}
+
+	defaults, _ := NewClient("https://clock.example.com")
+	defaultTransport := defaults.httpClient.Transport.(*http.Transport)
+	if defaultTransport.MaxIdleConns != http.DefaultTransport.(*http.Transport).MaxIdleConns {
+		t.Errorf("expected MaxIdleConns to be preserved from default transport, got %d", defaultTransport.MaxIdleConns)
+	}
+}
+
+func TestNewClient_IdleConnsUnlimited(t *testing.T) {
+	client, _ := NewClient("https://clock.example.com", WithMaxIdleConns(0))
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport type = %T, want *http.Transport", client.httpClient.Transport)
+	}
+
+	if transport.MaxIdleConns != 0 {
+		t.Errorf("MaxIdleConns = %d, want 0", transport.MaxIdleConns)
+	}
+	if transport.MaxIdleConnsPerHost != math.MaxInt {
+		t.Errorf("MaxIdleConnsPerHost = %d, want %d", transport.MaxIdleConnsPerHost, math.MaxInt)
+	}
+}
+
+func TestFetchStatus_Unauthorized(t *testing.T) {
+	for _, code := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(code)
+		}))
+
+		client, _ := NewClient(srv.URL, WithBasicAuth("user", "wrong"))
+		_, err := client.FetchStatus(context.Background(), testLogger())
+		if !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("error = %v, want ErrUnauthorized for status %d", err, code)
+		}
+
+		srv.Close()
+	}
+}
+
+func TestFetchConfig(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/config" {
+			t.Errorf("request path = %q, want /api/config", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		mustWrite(t, w, []byte(`{"data": {
+			"system": {"timezone": "Europe/Zurich"},
+			"ntp": {"stratum": 1},
+			"ptp": {"profile": "telecom-g8275.1"}
+		}}`))
+	}))
+	defer srv.Close()
+
+	client, _ := NewClient(srv.URL)
+	config, err := client.FetchConfig(context.Background(), testLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if config.Data.System.Timezone != "Europe/Zurich" {
+		t.Errorf("timezone = %q, want Europe/Zurich", config.Data.System.Timezone)
+	}
+	if config.Data.NTP.Stratum == nil || *config.Data.NTP.Stratum != 1 {
+		t.Errorf("stratum = %v, want 1", config.Data.NTP.Stratum)
+	}
+	if config.Data.PTP.Profile != "telecom-g8275.1" {
+		t.Errorf("PTP profile = %q, want telecom-g8275.1", config.Data.PTP.Profile)
+	}
+}
+
+func TestFetchConfig_Unavailable(t *testing.T) {
+	for _, code := range []int{http.StatusForbidden, http.StatusNotFound} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(code)
+		}))
+
+		var logs bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+		client, _ := NewClient(srv.URL)
+		_, err := client.FetchConfig(context.Background(), logger)
+		if !errors.Is(err, ErrEndpointUnavailable) {
+			t.Errorf("error = %v, want ErrEndpointUnavailable for status %d", err, code)
+		}
+		if logs.Len() > 0 {
+			t.Errorf("unexpected warning for status %d: %s", code, logs.String())
+		}
+
+		srv.Close()
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client, _ := NewClient(srv.URL)
+	if _, err := client.FetchConfig(context.Background(), testLogger()); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("error = %v, want ErrUnauthorized for status 401", err)
+	}
+}
@@ -0,0 +1,62 @@
+package ltosapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Categories of fetch failures returned by ErrorReason
+const (
+	ReasonTimeout = "timeout"
+	ReasonDNS     = "dns"
+	ReasonTLS     = "tls"
+	ReasonAuth    = "auth"
+	ReasonHTTP5xx = "http_5xx"
+	ReasonParse   = "parse"
+	ReasonOther   = "other"
+)
+
+// StatusError is returned when the API answers with an unexpected HTTP status code
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// ErrorReason maps an error returned by FetchStatus to one of the Reason categories
+func ErrorReason(err error) string {
+	var (
+		dnsErr      *net.DNSError
+		netErr      net.Error
+		statusErr   *StatusError
+		certErr     *tls.CertificateVerificationError
+		recordErr   tls.RecordHeaderError
+		unknownAuth x509.UnknownAuthorityError
+		hostnameErr x509.HostnameError
+		invalidErr  x509.CertificateInvalidError
+	)
+
+	switch {
+	case errors.As(err, &dnsErr):
+		return ReasonDNS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ReasonTimeout
+	case errors.As(err, &certErr), errors.As(err, &recordErr), errors.As(err, &unknownAuth),
+		errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return ReasonTLS
+	case errors.Is(err, ErrUnauthorized):
+		return ReasonAuth
+	case errors.As(err, &statusErr) && statusErr.StatusCode >= 500:
+		return ReasonHTTP5xx
+	case errors.Is(err, ErrUnmarshalResponse), errors.Is(err, ErrEmptyResponse), errors.Is(err, ErrUnexpectedContentType):
+		return ReasonParse
+	default:
+		return ReasonOther
+	}
+}
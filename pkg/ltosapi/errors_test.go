@@ -0,0 +1,49 @@
+package ltosapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestErrorReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"dns", &url.Error{Op: "Get", URL: "https://clock.invalid", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "clock.invalid", IsNotFound: true}}}, ReasonDNS},
+		{"deadline", fmt.Errorf("fetch: %w", context.DeadlineExceeded), ReasonTimeout},
+		{"auth", fmt.Errorf("%w (status code %d)", ErrUnauthorized, 401), ReasonAuth},
+		{"5xx", &StatusError{StatusCode: 503}, ReasonHTTP5xx},
+		{"4xx", &StatusError{StatusCode: 404}, ReasonOther},
+		{"unmarshal", fmt.Errorf("%w: %w", ErrUnmarshalResponse, errors.New("bad")), ReasonParse},
+		{"empty", ErrEmptyResponse, ReasonParse},
+		{"content type", fmt.Errorf("%w %q", ErrUnexpectedContentType, "text/html"), ReasonParse},
+		{"other", errors.New("connection refused"), ReasonOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorReason(tt.err); got != tt.expected {
+				t.Errorf("ErrorReason(%v) = %q, want %q", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestErrorReason_UntrustedCertificate(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	client, _ := NewClient(srv.URL)
+	_, err := client.FetchStatus(context.Background(), testLogger())
+	if got := ErrorReason(err); got != ReasonTLS {
+		t.Errorf("ErrorReason(%v) = %q, want %q", err, got, ReasonTLS)
+	}
+}
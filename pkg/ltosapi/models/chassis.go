@@ -8,14 +8,16 @@ import (
 )
 
 type Chassis struct {
+	Index             int    `json:"-"`
 	BackplaneRevision string `json:"backplane-revision"`
 	Slots             []Slot `json:"slots"`
 }
 
 type Slot struct {
-	Type   string      `json:"slot-type"`
-	Name   string      `json:"slot-id"`
-	Module *SlotModule `json:"module,omitempty"`
+	Type    string      `json:"slot-type"`
+	Name    string      `json:"slot-id"`
+	Chassis int         `json:"-"` // index of the chassis the slot is in, set by StatusData.Slots
+	Module  *SlotModule `json:"module,omitempty"`
 }
 
 const (
@@ -44,6 +46,83 @@ type SyncStatus struct {
 	OscillatorType string       `json:"osc-type"`
 	TimeQuality    *TimeQuality `json:"est-time-quality"`
 	ClockStatus    ClockStatus  `json:"clock-status"`
+
+	// Reference and RefType name the selected reference clock, only reported on the system's sync status
+	Reference string `json:"reference"`
+	RefType   string `json:"ref-type"`
+}
+
+// Classes of the oscillator of a clock module
+const (
+	OscillatorClassTCXO     = "tcxo"
+	OscillatorClassOCXO     = "ocxo"
+	OscillatorClassRubidium = "rubidium"
+	OscillatorClassOther    = "other"
+	OscillatorClassUnknown  = "unknown"
+)
+
+// OscillatorClass returns the class of the oscillator type, e.g. "ocxo" for the "ocxo-lq" and "ocxo-hq" variants
+func (s SyncStatus) OscillatorClass() string {
+	oscType := strings.ToLower(strings.TrimSpace(s.OscillatorType))
+	switch {
+	case oscType == "":
+		return OscillatorClassUnknown
+	case strings.HasPrefix(oscType, "tcxo"):
+		return OscillatorClassTCXO
+	case strings.HasPrefix(oscType, "ocxo"):
+		return OscillatorClassOCXO
+	case strings.HasPrefix(oscType, "rubidium"), strings.HasPrefix(oscType, "rb"):
+		return OscillatorClassRubidium
+	default:
+		return OscillatorClassOther
+	}
+}
+
+// Categories of the time source a device is disciplined by
+const (
+	TimeSourceGNSS     = "gnss"
+	TimeSourceLongwave = "longwave"
+	TimeSourcePTP      = "ptp"
+	TimeSourceNTP      = "ntp"
+	TimeSourcePPS      = "pps"
+	TimeSourceFreerun  = "freerun"
+	TimeSourceOther    = "other"
+)
+
+// timeSourceRefTypes maps substrings of the reference type, e.g. "gps" or "dcf77-pzf-receiver", to time source
+// categories. PTP and NTP are matched before GNSS so e.g. a PTP input card fed by GPS counts as PTP.
+var timeSourceRefTypes = []struct {
+	substr string
+	source string
+}{
+	{"ptp", TimeSourcePTP},
+	{"ntp", TimeSourceNTP},
+	{"gps", TimeSourceGNSS},
+	{"gns", TimeSourceGNSS},
+	{"glonass", TimeSourceGNSS},
+	{"galileo", TimeSourceGNSS},
+	{"beidou", TimeSourceGNSS},
+	{"dcf77", TimeSourceLongwave},
+	{"pzf", TimeSourceLongwave},
+	{"msf", TimeSourceLongwave},
+	{"wwvb", TimeSourceLongwave},
+	{"pps", TimeSourcePPS},
+}
+
+// TimeSource returns the category of the reference the clock is currently disciplined by, or freerun if the
+// clock is not synchronized to any reference
+func (s SyncStatus) TimeSource() string {
+	if !s.ClockStatus.IsSynchronized() {
+		return TimeSourceFreerun
+	}
+
+	refType := strings.ToLower(s.RefType)
+	for _, rt := range timeSourceRefTypes {
+		if strings.Contains(refType, rt.substr) {
+			return rt.source
+		}
+	}
+	return TimeSourceOther
 }
 
 type TimeQuality time.Duration
@@ -70,6 +149,42 @@ func (t *TimeQuality) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ptpClockAccuracyThresholds maps upper bounds on the time error to IEEE 1588-2008 clockAccuracy values
+var ptpClockAccuracyThresholds = []struct {
+	bound    time.Duration
+	accuracy uint8
+}{
+	{25 * time.Nanosecond, 0x20},
+	{100 * time.Nanosecond, 0x21},
+	{250 * time.Nanosecond, 0x22},
+	{time.Microsecond, 0x23},
+	{2500 * time.Nanosecond, 0x24},
+	{10 * time.Microsecond, 0x25},
+	{25 * time.Microsecond, 0x26},
+	{100 * time.Microsecond, 0x27},
+	{250 * time.Microsecond, 0x28},
+	{time.Millisecond, 0x29},
+	{2500 * time.Microsecond, 0x2A},
+	{10 * time.Millisecond, 0x2B},
+	{25 * time.Millisecond, 0x2C},
+	{100 * time.Millisecond, 0x2D},
+	{250 * time.Millisecond, 0x2E},
+	{time.Second, 0x2F},
+	{10 * time.Second, 0x30},
+}
+
+const ptpClockAccuracyAbove10s = 0x31
+
+// PTPClockAccuracy returns the IEEE 1588-2008 clockAccuracy enumeration value for the time quality
+func (t TimeQuality) PTPClockAccuracy() uint8 {
+	for _, th := range ptpClockAccuracyThresholds {
+		if time.Duration(t) <= th.bound {
+			return th.accuracy
+		}
+	}
+	return ptpClockAccuracyAbove10s
+}
+
 type ClockStatus struct {
 	Clock      string `json:"clock"`
 	Oscillator string `json:"oscillator"`
@@ -83,9 +198,37 @@ func (cs ClockStatus) IsOscillatorWarmedUp() bool {
 	return cs.Oscillator == "warmed-up"
 }
 
+func (cs ClockStatus) IsHoldover() bool {
+	return cs.Clock == "holdover"
+}
+
+// IEEE 1588-2008 clockClass values for a grandmaster
+const (
+	PTPClockClassLocked       = 6
+	PTPClockClassHoldover     = 7
+	PTPClockClassDegraded     = 52
+	PTPClockClassUncalibrated = 248
+)
+
+// PTPClockClass derives the IEEE 1588-2008 clockClass a grandmaster would announce from the clock status.
+// A clock that lost its reference after warming up is reported as degraded (52), a clock whose oscillator
+// is still warming up as the default class (248).
+func (cs ClockStatus) PTPClockClass() uint8 {
+	switch {
+	case cs.IsSynchronized():
+		return PTPClockClassLocked
+	case cs.IsHoldover():
+		return PTPClockClassHoldover
+	case cs.IsOscillatorWarmedUp():
+		return PTPClockClassDegraded
+	default:
+		return PTPClockClassUncalibrated
+	}
+}
+
 type Satellites struct {
-	InView    float64 `json:"satellites-in-view"`
-	Good      float64 `json:"good-satellites"`
+	InView    Number  `json:"satellites-in-view"`
+	Good      Number  `json:"good-satellites"`
 	Latitude  float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
 	Altitude  float64 `json:"altitude"`
@@ -113,4 +256,10 @@ type DCF77 struct {
 	Name          string  `json:"ref-type"`
 	Correlation   float64 `json:"correlation"`
 	FieldStrength float64 `json:"field-strength"`
+	PCPS          *PCPS   `json:"pcps,omitempty"`
+}
+
+// PCPS holds the status flags of a time code receiver
+type PCPS struct {
+	IsSynchronized bool `json:"syncd"`
 }
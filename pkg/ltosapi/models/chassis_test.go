@@ -67,3 +67,99 @@ func TestClockStatus_IsOscillatorWarmedUp(t *testing.T) {
 		t.Error("expected false for 'warming-up'")
 	}
 }
+
+func TestTimeQuality_PTPClockAccuracy(t *testing.T) {
+	tests := []struct {
+		name     string
+		quality  time.Duration
+		expected uint8
+	}{
+		{"exactly 25ns", 25 * time.Nanosecond, 0x20},
+		{"100ns", 100 * time.Nanosecond, 0x21},
+		{"just above 100ns", 101 * time.Nanosecond, 0x22},
+		{"100µs", 100 * time.Microsecond, 0x27},
+		{"25ms", 25 * time.Millisecond, 0x2C},
+		{"10s", 10 * time.Second, 0x30},
+		{"above 10s", time.Minute, 0x31},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TimeQuality(tt.quality).PTPClockAccuracy(); got != tt.expected {
+				t.Errorf("got %#x, want %#x", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClockStatus_PTPClockClass(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   ClockStatus
+		expected uint8
+	}{
+		{"synchronized", ClockStatus{Clock: "synchronized", Oscillator: "warmed-up"}, PTPClockClassLocked},
+		{"holdover", ClockStatus{Clock: "holdover", Oscillator: "warmed-up"}, PTPClockClassHoldover},
+		{"free-running warmed up", ClockStatus{Clock: "not-synchronized", Oscillator: "warmed-up"}, PTPClockClassDegraded},
+		{"warming up", ClockStatus{Clock: "not-synchronized", Oscillator: "warming-up"}, PTPClockClassUncalibrated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.PTPClockClass(); got != tt.expected {
+				t.Errorf("got %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSyncStatus_OscillatorClass(t *testing.T) {
+	tests := []struct {
+		oscType  string
+		expected string
+	}{
+		{"tcxo", OscillatorClassTCXO},
+		{"ocxo-lq", OscillatorClassOCXO},
+		{"OCXO-HQ", OscillatorClassOCXO},
+		{"rubidium", OscillatorClassRubidium},
+		{"rb-sa", OscillatorClassRubidium},
+		{"xo", OscillatorClassOther},
+		{"", OscillatorClassUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.oscType, func(t *testing.T) {
+			if got := (SyncStatus{OscillatorType: tt.oscType}).OscillatorClass(); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSyncStatus_TimeSource(t *testing.T) {
+	synced := ClockStatus{Clock: "synchronized"}
+
+	tests := []struct {
+		name     string
+		status   SyncStatus
+		expected string
+	}{
+		{"gps", SyncStatus{RefType: "gps", ClockStatus: synced}, TimeSourceGNSS},
+		{"gnss", SyncStatus{RefType: "GNS181", ClockStatus: synced}, TimeSourceGNSS},
+		{"dcf77", SyncStatus{RefType: "dcf77-pzf-receiver", ClockStatus: synced}, TimeSourceLongwave},
+		{"ptp", SyncStatus{RefType: "ptp-slave", ClockStatus: synced}, TimeSourcePTP},
+		{"ntp", SyncStatus{RefType: "ntp-client", ClockStatus: synced}, TimeSourceNTP},
+		{"pps", SyncStatus{RefType: "pps-in", ClockStatus: synced}, TimeSourcePPS},
+		{"unknown", SyncStatus{RefType: "10mhz-freqin", ClockStatus: synced}, TimeSourceOther},
+		{"holdover", SyncStatus{RefType: "gps", ClockStatus: ClockStatus{Clock: "holdover"}}, TimeSourceFreerun},
+		{"not synchronized", SyncStatus{RefType: "gps", ClockStatus: ClockStatus{Clock: "not-synchronized"}}, TimeSourceFreerun},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.TimeSource(); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+// ConfigResponse is the response of the /api/config endpoint. Only the settings that pair with a status metric are
+// modeled, anything else the device reports is ignored.
+type ConfigResponse struct {
+	Data ConfigData `json:"data"`
+}
+
+type ConfigData struct {
+	System ConfigSystem `json:"system"`
+	NTP    ConfigNTP    `json:"ntp"`
+	PTP    ConfigPTP    `json:"ptp"`
+}
+
+type ConfigSystem struct {
+	Timezone string `json:"timezone"`
+}
+
+type ConfigNTP struct {
+	Stratum *Number `json:"stratum"` // nil if the device does not report it
+}
+
+type ConfigPTP struct {
+	Profile string `json:"profile"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DurationSeconds is a duration in seconds that some firmware versions report as a number or numeric string and
+// others in the human-readable form of uptime(1), e.g. "1 day, 12:13:14"
+type DurationSeconds float64
+
+func (d *DurationSeconds) UnmarshalJSON(data []byte) error {
+	var n Number
+	numErr := n.UnmarshalJSON(data)
+	if numErr == nil {
+		*d = DurationSeconds(n)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return numErr
+	}
+
+	seconds, err := parseHumanDuration(s)
+	if err != nil {
+		// A type error lets the decoder fill in the path of the offending field
+		return &json.UnmarshalTypeError{Value: "unparseable duration " + strconv.Quote(s), Type: reflect.TypeOf(float64(0))}
+	}
+
+	*d = DurationSeconds(seconds)
+	return nil
+}
+
+// humanDurationRe matches durations of the form "[N day[s], ][H]H:MM[:SS]", with at least one of both parts
+var humanDurationRe = regexp.MustCompile(`^(?:(\d+)\s+days?,?\s*)?(?:(\d+):(\d{2})(?::(\d{2}(?:\.\d+)?))?)?$`)
+
+// parseHumanDuration parses a duration such as "1 day, 12:13:14", "3 days, 4:05" or "12:13:14" to seconds
+func parseHumanDuration(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	matches := humanDurationRe.FindStringSubmatch(s)
+	if s == "" || matches == nil {
+		return 0, fmt.Errorf("unrecognized duration %q", s)
+	}
+
+	var seconds float64
+	for i, unit := range []float64{86400, 3600, 60, 1} {
+		if matches[i+1] == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(matches[i+1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse duration %q: %v", s, err)
+		}
+		seconds += v * unit
+	}
+
+	return seconds, nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDurationSeconds_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  DurationSeconds
+		expectErr bool
+	}{
+		{"number", `130988.25`, 130988.25, false},
+		{"numeric string", `"130988"`, 130988, false},
+		{"days and time", `"1 day, 12:13:14"`, 86400 + 12*3600 + 13*60 + 14, false},
+		{"plural days", `"3 days, 04:05:06"`, 3*86400 + 4*3600 + 5*60 + 6, false},
+		{"days and hours minutes", `"3 days, 4:05"`, 3*86400 + 4*3600 + 5*60, false},
+		{"time only", `"12:13:14"`, 12*3600 + 13*60 + 14, false},
+		{"days only", `"2 days"`, 2 * 86400, false},
+		{"unparseable string", `"a while"`, 0, true},
+		{"empty string", `""`, 0, true},
+		{"bool", `true`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d DurationSeconds
+			err := json.Unmarshal([]byte(tt.input), &d)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if d != tt.expected {
+				t.Errorf("got %v, want %v", d, tt.expected)
+			}
+		})
+	}
+}
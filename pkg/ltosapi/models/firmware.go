@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FirmwareVersion holds the numeric components of an LTOS firmware version string such as "fw_7.10.008"
+type FirmwareVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseFirmwareVersion parses firmware versions like "fw_7.10.008", "7.06.014-light" or "7.10".
+// A leading "fw_" prefix and any suffix after a dash are ignored, a missing patch component defaults to 0.
+func ParseFirmwareVersion(s string) (FirmwareVersion, error) {
+	v := strings.TrimSpace(s)
+	v = strings.TrimPrefix(strings.ToLower(v), "fw_")
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		v = v[:i]
+	}
+
+	components, err := parseVersionComponents(v)
+	if err != nil {
+		return FirmwareVersion{}, fmt.Errorf("invalid firmware version %q", s)
+	}
+
+	return FirmwareVersion{Major: components[0], Minor: components[1], Patch: components[2]}, nil
+}
+
+// parseVersionComponents splits a dotted "major.minor[.patch]" version into its numeric components
+func parseVersionComponents(v string) ([3]int, error) {
+	var components [3]int
+
+	parts := strings.Split(v, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return components, fmt.Errorf("expected 2 or 3 components, got %d", len(parts))
+	}
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return components, fmt.Errorf("invalid version component %q", part)
+		}
+		components[i] = n
+	}
+
+	return components, nil
+}
+
+// Numeric returns the version encoded as major*10000 + minor*100 + patch, e.g. 7.10.008 becomes 71008
+func (v FirmwareVersion) Numeric() float64 {
+	return float64(v.Major*10000 + v.Minor*100 + v.Patch)
+}
@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+func TestParseFirmwareVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected FirmwareVersion
+		numeric  float64
+	}{
+		{"fw prefix", "fw_7.10.008", FirmwareVersion{7, 10, 8}, 71008},
+		{"fw prefix with suffix", "fw_7.06.014-light", FirmwareVersion{7, 6, 14}, 70614},
+		{"no prefix", "7.06.14", FirmwareVersion{7, 6, 14}, 70614},
+		{"missing patch", "fw_7.10", FirmwareVersion{7, 10, 0}, 71000},
+		{"whitespace trimmed", "  fw_6.24.26 ", FirmwareVersion{6, 24, 26}, 62426},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := ParseFirmwareVersion(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if v != tt.expected {
+				t.Errorf("got %+v, want %+v", v, tt.expected)
+			}
+			if got := v.Numeric(); got != tt.numeric {
+				t.Errorf("Numeric() = %v, want %v", got, tt.numeric)
+			}
+		})
+	}
+}
+
+func TestParseFirmwareVersion_Invalid(t *testing.T) {
+	for _, input := range []string{"", "fw_", "7", "no version information", "7.x.1", "7.1.2.3"} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := ParseFirmwareVersion(input); err == nil {
+				t.Errorf("expected error for %q", input)
+			}
+		})
+	}
+}
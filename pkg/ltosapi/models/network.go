@@ -1,7 +1,21 @@
 package models
 
 type Network struct {
-	Ports []Port `json:"ports"`
+	Ports      []Port      `json:"ports"`
+	Interfaces []Interface `json:"interfaces"`
+}
+
+// Interface is a virtual network interface bound to a port, e.g. "lan0:0"
+type Interface struct {
+	Name      string             `json:"ifname"`
+	Addresses []InterfaceAddress `json:"addresses"`
+}
+
+type InterfaceAddress struct {
+	Type       string `json:"addresstype"`
+	Assignment string `json:"iftype"`
+	Address    string `json:"address"`
+	Subnet     string `json:"subnet"`
 }
 
 type Port struct {
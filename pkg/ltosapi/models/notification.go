@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -10,16 +11,52 @@ type Notification struct {
 	Events []Event `json:"events"`
 }
 
+// EventSourceUnknown is the source of events whose object-id matches no known subsystem
+const EventSourceUnknown = "unknown"
+
+// EventSourceAntenna is the source of the receiver antenna events, e.g. "antenna-faulty" or "antenna-reconnect"
+const EventSourceAntenna = "antenna"
+
+// eventSources maps object-id prefixes to the subsystem an event originates from. The API does not report the source
+// itself, so it is derived from the object-id to keep the set of source values bounded.
+var eventSources = []struct {
+	prefix string
+	source string
+}{
+	{"antenna-", EventSourceAntenna},
+	{"auto-update-", "firmware"},
+	{"cluster-", "cluster"},
+	{"device-configuration-", "system"},
+	{"faillock", "security"},
+	{"https-certificate-", "https"},
+	{"self-signed-https-certificate-", "https"},
+	{"leapsecond-", "clock"},
+	{"leap-second-", "clock"},
+	{"oscillator-", "clock"},
+	{"refclock-", "refclock"},
+	{"network-", "network"},
+	{"ntp-", "ntp"},
+	{"sync-monitor", "syncmon"},
+	{"low-system-resources", "system"},
+	{"sufficient-system-resources", "system"},
+	{"system-", "system"},
+	{"normal-operation", "system"},
+}
+
 type Event struct {
 	Type              string
 	Name              string
+	Active            bool // whether the event is currently raised rather than cleared
 	LastTriggeredUnix float64
+	// LastTriggered is the device's wall clock time of the last trigger in UTC, zero if never triggered
+	LastTriggered time.Time
 }
 
 func (e *Event) UnmarshalJSON(data []byte) error {
 	aux := struct {
 		Type          string `json:"type"`
 		Name          string `json:"object-id"`
+		Triggered     Number `json:"triggered"`
 		LastTriggered string `json:"last-triggered"`
 	}{}
 
@@ -29,15 +66,38 @@ func (e *Event) UnmarshalJSON(data []byte) error {
 
 	e.Type = aux.Type
 	e.Name = aux.Name
+	e.Active = aux.Triggered != 0
 
 	if aux.LastTriggered != "never" {
-		// time.Parse without a timezone defaults to UTC. The Meinberg LTOS API returns timestamps without timezone information, and is assumed to use UTC.
+		// time.Parse without a timezone defaults to UTC. The Meinberg LTOS API returns timestamps without timezone information, see LastTriggeredUnixIn for devices not running in UTC.
 		parsedTime, err := time.Parse("2006-01-02T15:04:05", aux.LastTriggered)
 		if err != nil {
 			return fmt.Errorf("failed to parse last-triggered timestamp %q: %w", aux.LastTriggered, err)
 		}
+		e.LastTriggered = parsedTime
 		e.LastTriggeredUnix = float64(parsedTime.Unix())
 	}
 
 	return nil
 }
+
+// LastTriggeredUnixIn returns the last trigger time as seconds since UNIX epoch, interpreting the device's wall clock
+// time in the given location. Returns 0 if the event never triggered.
+func (e Event) LastTriggeredUnixIn(loc *time.Location) float64 {
+	if e.LastTriggered.IsZero() {
+		return 0
+	}
+
+	t := e.LastTriggered
+	return float64(time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc).Unix())
+}
+
+// Source returns the subsystem the event originates from, derived from its object-id
+func (e Event) Source() string {
+	for _, es := range eventSources {
+		if strings.HasPrefix(e.Name, es.prefix) {
+			return es.source
+		}
+	}
+	return EventSourceUnknown
+}
@@ -12,30 +12,38 @@ func TestEvent_UnmarshalJSON(t *testing.T) {
 		input            string
 		expectedType     string
 		expectedName     string
+		expectedActive   bool
 		expectedLastUnix float64
 		expectErr        bool
 	}{
 		{
 			"triggered event",
-			`{"type":"warning","object-id":"ntp-not-synchronized","last-triggered":"2025-03-15T12:30:00"}`,
-			"warning", "ntp-not-synchronized",
+			`{"type":"warning","object-id":"ntp-not-synchronized","triggered":1,"last-triggered":"2025-03-15T12:30:00"}`,
+			"warning", "ntp-not-synchronized", true,
 			float64(time.Date(2025, 3, 15, 12, 30, 0, 0, time.UTC).Unix()),
 			false,
 		},
 		{
 			"never triggered",
-			`{"type":"error","object-id":"antenna-fault","last-triggered":"never"}`,
-			"error", "antenna-fault", 0, false,
+			`{"type":"error","object-id":"antenna-fault","triggered":0,"last-triggered":"never"}`,
+			"error", "antenna-fault", false, 0, false,
+		},
+		{
+			"cleared event",
+			`{"type":"info","object-id":"ntp-sync","triggered":0,"last-triggered":"2025-03-15T12:30:00"}`,
+			"info", "ntp-sync", false,
+			float64(time.Date(2025, 3, 15, 12, 30, 0, 0, time.UTC).Unix()),
+			false,
 		},
 		{
 			"invalid timestamp",
 			`{"type":"warning","object-id":"test","last-triggered":"not-a-time"}`,
-			"", "", 0, true,
+			"", "", false, 0, true,
 		},
 		{
 			"invalid json",
 			`{broken}`,
-			"", "", 0, true,
+			"", "", false, 0, true,
 		},
 	}
 
@@ -58,9 +66,78 @@ func TestEvent_UnmarshalJSON(t *testing.T) {
 			if e.Name != tt.expectedName {
 				t.Errorf("Name: got %q, want %q", e.Name, tt.expectedName)
 			}
+			if e.Active != tt.expectedActive {
+				t.Errorf("Active: got %v, want %v", e.Active, tt.expectedActive)
+			}
 			if e.LastTriggeredUnix != tt.expectedLastUnix {
 				t.Errorf("LastTriggeredUnix: got %f, want %f", e.LastTriggeredUnix, tt.expectedLastUnix)
 			}
 		})
 	}
 }
+
+func TestEvent_LastTriggeredUnixIn(t *testing.T) {
+	zurich, err := time.LoadLocation("Europe/Zurich")
+	if err != nil {
+		t.Skipf("timezone database not available: %v", err)
+	}
+
+	var e Event
+	if err := json.Unmarshal([]byte(`{"type":"info","object-id":"ntp-sync","last-triggered":"2026-02-10T13:49:32"}`), &e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		loc      *time.Location
+		expected float64
+	}{
+		{"UTC device", time.UTC, float64(time.Date(2026, 2, 10, 13, 49, 32, 0, time.UTC).Unix())},
+		{"non-UTC device", zurich, float64(time.Date(2026, 2, 10, 12, 49, 32, 0, time.UTC).Unix())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := e.LastTriggeredUnixIn(tt.loc); got != tt.expected {
+				t.Errorf("LastTriggeredUnixIn() = %f, want %f", got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("never triggered", func(t *testing.T) {
+		var never Event
+		if err := json.Unmarshal([]byte(`{"type":"info","object-id":"x","last-triggered":"never"}`), &never); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := never.LastTriggeredUnixIn(zurich); got != 0 {
+			t.Errorf("LastTriggeredUnixIn() = %f, want 0", got)
+		}
+	})
+}
+
+func TestEvent_Source(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"antenna-faulty", "antenna"},
+		{"ntp-not-sync", "ntp"},
+		{"refclock-1-not-sync", "refclock"},
+		{"network-link-down", "network"},
+		{"sync-monitor-alert", "syncmon"},
+		{"leapsecond-announced", "clock"},
+		{"leap-second-announced", "clock"},
+		{"faillock:-user-banned", "security"},
+		{"system-reboot", "system"},
+		{"something-new", EventSourceUnknown},
+		{"", EventSourceUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Event{Name: tt.name}).Source(); got != tt.expected {
+				t.Errorf("Source() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -78,12 +79,28 @@ type NTPAssociation struct {
 	Offset     *float64 `json:"offset,omitempty"`
 	Delay      *float64 `json:"delay,omitempty"`
 	Dispersion *float64 `json:"dispersion,omitempty"`
+	Reach      *float64 `json:"reach,omitempty"`
+	Configured *bool    `json:"status-peer-configured,omitempty"`
 }
 
 func (a NTPAssociation) IsSys() bool {
 	return a.AssociationID == 0
 }
 
+// IsRefclock reports whether the association is a local reference clock, which ntpd addresses as 127.127.t.u
+func (a NTPAssociation) IsRefclock() bool {
+	return strings.HasPrefix(a.Address, "127.127.")
+}
+
+// IsUnreachableUpstream reports whether the association is a configured upstream server that answered none of
+// the last 8 polls. Peers that do not report their configured state are assumed to be configured.
+func (a NTPAssociation) IsUnreachableUpstream() bool {
+	if a.IsSys() || a.IsRefclock() || (a.Configured != nil && !*a.Configured) {
+		return false
+	}
+	return a.Reach != nil && *a.Reach == 0
+}
+
 func (a NTPAssociation) PrecisionSeconds() float64 {
 	return math.Pow(2, a.Precision)
 }
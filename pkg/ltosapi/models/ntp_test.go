@@ -88,6 +88,34 @@ func TestNTPAssociation_IsSys(t *testing.T) {
 	}
 }
 
+func TestNTPAssociation_IsUnreachableUpstream(t *testing.T) {
+	reach := func(v float64) *float64 { return &v }
+	configured := func(v bool) *bool { return &v }
+
+	tests := []struct {
+		name     string
+		assoc    NTPAssociation
+		expected bool
+	}{
+		{"unreachable server", NTPAssociation{AssociationID: 1, Address: "192.0.2.1", Reach: reach(0)}, true},
+		{"reachable server", NTPAssociation{AssociationID: 1, Address: "192.0.2.1", Reach: reach(255)}, false},
+		{"partially reachable server", NTPAssociation{AssociationID: 1, Address: "192.0.2.1", Reach: reach(1)}, false},
+		{"configured unreachable server", NTPAssociation{AssociationID: 1, Address: "192.0.2.1", Reach: reach(0), Configured: configured(true)}, true},
+		{"ephemeral association", NTPAssociation{AssociationID: 1, Address: "192.0.2.1", Reach: reach(0), Configured: configured(false)}, false},
+		{"refclock", NTPAssociation{AssociationID: 1, Address: "127.127.8.0", Reach: reach(0)}, false},
+		{"sys", NTPAssociation{AssociationID: 0, Address: "127.0.0.1", Reach: reach(0)}, false},
+		{"reach not reported", NTPAssociation{AssociationID: 1, Address: "192.0.2.1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.assoc.IsUnreachableUpstream(); got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestNTPAssociation_PrecisionSeconds(t *testing.T) {
 	a := NTPAssociation{Precision: -20}
 	got := a.PrecisionSeconds()
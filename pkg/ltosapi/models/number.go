@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Number is a float64 that some firmware versions report as a JSON number and others as a numeric
+// string (e.g. "uptime": "130988")
+type Number float64
+
+func (n *Number) UnmarshalJSON(data []byte) error {
+	var f float64
+	if err := json.Unmarshal(data, &f); err == nil {
+		*n = Number(f)
+		return nil
+	}
+
+	// A type error lets the decoder fill in the path of the offending field
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return &json.UnmarshalTypeError{Value: "non-numeric value " + string(data), Type: reflect.TypeOf(f)}
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return &json.UnmarshalTypeError{Value: "non-numeric string " + strconv.Quote(s), Type: reflect.TypeOf(f)}
+	}
+
+	*n = Number(f)
+	return nil
+}
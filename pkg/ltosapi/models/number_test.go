@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNumber_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  Number
+		expectErr bool
+	}{
+		{"number", `130988`, 130988, false},
+		{"float", `1.06`, 1.06, false},
+		{"numeric string", `"130988"`, 130988, false},
+		{"float string", `"1.06"`, 1.06, false},
+		{"padded string", `" 14 "`, 14, false},
+		{"non-numeric string", `"n/a"`, 0, true},
+		{"empty string", `""`, 0, true},
+		{"bool", `true`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n Number
+			err := json.Unmarshal([]byte(tt.input), &n)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != tt.expected {
+				t.Errorf("got %v, want %v", n, tt.expected)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -8,10 +9,18 @@ import (
 
 type SerialNumber string
 
+// UnmarshalJSON accepts the serial number as a string or as a JSON number. Numbers are taken verbatim from the
+// document, as decoding them as float64 would round serials beyond 2^53.
 func (s *SerialNumber) UnmarshalJSON(data []byte) error {
 	var raw string
 	if err := json.Unmarshal(data, &raw); err != nil {
-		return fmt.Errorf("failed to unmarshal serial number: %v", err)
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var num json.Number
+		if numErr := dec.Decode(&num); numErr != nil {
+			return fmt.Errorf("failed to unmarshal serial number: %v", err)
+		}
+		raw = num.String()
 	}
 
 	sn := strings.TrimSpace(raw)
@@ -19,6 +19,8 @@ func TestSerialNumber_UnmarshalJSON(t *testing.T) {
 		{"n/a", `"n/a"`, ""},
 		{"na", `"na"`, ""},
 		{"none", `"none"`, ""},
+		{"number", `123456`, "123456"},
+		{"number beyond float64 precision", `12345678901234567891`, "12345678901234567891"},
 	}
 
 	for _, tt := range tests {
@@ -33,3 +35,12 @@ func TestSerialNumber_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestSerialNumber_UnmarshalJSON_Invalid(t *testing.T) {
+	for _, input := range []string{`true`, `{"serial": "ABC123"}`, `[1]`} {
+		var sn SerialNumber
+		if err := json.Unmarshal([]byte(input), &sn); err == nil {
+			t.Errorf("expected error for %s, got %q", input, sn)
+		}
+	}
+}
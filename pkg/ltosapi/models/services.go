@@ -0,0 +1,11 @@
+package models
+
+// Services reports the run state of the device's daemons, keyed by service name, e.g. "ntp" or "ssh"
+type Services struct {
+	Network map[string]Service `json:"network"`
+	Global  map[string]Service `json:"global"`
+}
+
+type Service struct {
+	Running bool `json:"running"`
+}
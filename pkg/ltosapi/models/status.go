@@ -1,8 +1,25 @@
 package models
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Range of REST API major versions the models have been verified against
+const (
+	MinSupportedAPIMajorVersion = 10
+	MaxSupportedAPIMajorVersion = 20
+)
+
 type StatusResponse struct {
 	SystemInformation SystemInformation `json:"system-information"`
 	Data              StatusData        `json:"data"`
+	Changes           Changes           `json:"changes"`
 }
 
 type SystemInformation struct {
@@ -17,10 +34,83 @@ type StatusData struct {
 	System       System           `json:"system"`
 	Notification Notification     `json:"notification"`
 	Network      Network          `json:"network"`
-	Chassis      Chassis          `json:"chassis0"`
+	Chassis      []Chassis        `json:"-"` // chassis0, chassis1, ... ordered by index
 	NTP          []NTPAssociation `json:"ntp"`
+	Services     Services         `json:"services"`
+}
+
+var chassisKeyRe = regexp.MustCompile(`^chassis(\d+)$`)
+
+// UnmarshalJSON collects all "chassisN" objects, as stacked systems report expansion chassis next to chassis0
+func (d *StatusData) UnmarshalJSON(data []byte) error {
+	type statusData StatusData
+	var aux statusData
+	if err := json.Unmarshal(data, &aux); err != nil {
+		// encoding/json does not add the path of this object to errors returned from a custom unmarshaler
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			typeErr.Field = "data." + typeErr.Field
+		}
+		return fmt.Errorf("failed to unmarshal status data: %w", err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal status data: %v", err)
+	}
+
+	for key, value := range raw {
+		matches := chassisKeyRe.FindStringSubmatch(key)
+		if matches == nil {
+			continue
+		}
+		index, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return fmt.Errorf("failed to parse chassis index of %q: %v", key, err)
+		}
+
+		var chassis Chassis
+		if err := json.Unmarshal(value, &chassis); err != nil {
+			return fmt.Errorf("failed to unmarshal %s: %v", key, err)
+		}
+		chassis.Index = index
+		aux.Chassis = append(aux.Chassis, chassis)
+	}
+
+	sort.Slice(aux.Chassis, func(i, j int) bool { return aux.Chassis[i].Index < aux.Chassis[j].Index })
+
+	*d = StatusData(aux)
+	return nil
+}
+
+// Slots returns the slots of all chassis, each carrying the index of its chassis
+func (d StatusData) Slots() []Slot {
+	var slots []Slot
+	for _, chassis := range d.Chassis {
+		for _, slot := range chassis.Slots {
+			slot.Chassis = chassis.Index
+			slots = append(slots, slot)
+		}
+	}
+	return slots
+}
+
+// Changes reports configuration changes that have not yet been applied on the device
+type Changes struct {
+	PendingChanges *float64 `json:"pending-changes,omitempty"`
 }
 
 type RestAPI struct {
 	Version string `json:"api-version"`
 }
+
+// IsSupported reports whether the device's REST API version lies within the known-compatible range
+func (r RestAPI) IsSupported() bool {
+	components, err := parseVersionComponents(strings.TrimSpace(r.Version))
+	if err != nil {
+		return false
+	}
+
+	major := components[0]
+	return major >= MinSupportedAPIMajorVersion && major <= MaxSupportedAPIMajorVersion
+}
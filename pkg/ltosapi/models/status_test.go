@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRestAPI_IsSupported(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected bool
+	}{
+		{"20.05.013", true},
+		{"10.21.016", true},
+		{"15.00", true},
+		{"9.99.999", false},
+		{"21.00.000", false},
+		{"", false},
+		{"unknown", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := (RestAPI{Version: tt.version}).IsSupported(); got != tt.expected {
+				t.Errorf("IsSupported() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStatusData_MultipleChassis(t *testing.T) {
+	input := `{
+		"rest-api": {"api-version": "20.05.013"},
+		"chassis1": {"slots": [{"slot-id": "clk1", "slot-type": "clk", "module": {}}]},
+		"chassis0": {"backplane-revision": "1", "slots": [
+			{"slot-id": "cpu", "slot-type": "cpu", "module": {}},
+			{"slot-id": "clk1", "slot-type": "clk", "module": {}}
+		]},
+		"chassis-xhe": {"slots": [{"slot-id": "ignored"}]}
+	}`
+
+	var d StatusData
+	if err := json.Unmarshal([]byte(input), &d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(d.Chassis) != 2 {
+		t.Fatalf("got %d chassis, want 2", len(d.Chassis))
+	}
+	if d.Chassis[0].Index != 0 || d.Chassis[1].Index != 1 {
+		t.Errorf("chassis not ordered by index: %d, %d", d.Chassis[0].Index, d.Chassis[1].Index)
+	}
+	if d.RestAPI.Version != "20.05.013" {
+		t.Errorf("RestAPI.Version = %q, want %q", d.RestAPI.Version, "20.05.013")
+	}
+
+	var slots []string
+	for _, slot := range d.Slots() {
+		slots = append(slots, fmt.Sprintf("%d/%s", slot.Chassis, slot.Name))
+	}
+	expected := []string{"0/cpu", "0/clk1", "1/clk1"}
+	if strings.Join(slots, ",") != strings.Join(expected, ",") {
+		t.Errorf("slots = %v, want %v", slots, expected)
+	}
+}
+
+func TestStatusData_SingleChassis(t *testing.T) {
+	var d StatusData
+	if err := json.Unmarshal([]byte(`{"chassis0": {"slots": [{"slot-id": "clk1", "slot-type": "clk"}]}}`), &d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	slots := d.Slots()
+	if len(slots) != 1 || slots[0].Name != "clk1" || slots[0].Chassis != 0 {
+		t.Errorf("slots = %+v, want a single slot named clk1 in chassis 0", slots)
+	}
+}
@@ -6,13 +6,26 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type System struct {
-	UptimeSeconds float64 `json:"uptime"`
-	CPULoad       CPULoad `json:"cpuload"`
-	Memory        Memory  `json:"memory"`
-	Mounts        []Mount `json:"storage"`
+	UptimeSeconds  DurationSeconds `json:"uptime"`
+	CurrentTimeISO string          `json:"current-time-iso"`
+	CPULoad        CPULoad         `json:"cpuload"`
+	Memory         Memory          `json:"memory"`
+	Mounts         []Mount         `json:"storage"`
+
+	SyncStatus *SyncStatus `json:"sync-status,omitempty"`
+}
+
+// CurrentTime returns the device time at which the status was generated, if reported
+func (s System) CurrentTime() (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339Nano, s.CurrentTimeISO)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
 }
 
 type CPULoad struct {
@@ -24,6 +37,12 @@ type CPULoad struct {
 var (
 	memTotalRe = regexp.MustCompile(`(\d+)\s+kB\s+total`)
 	memFreeRe  = regexp.MustCompile(`(\d+)\s+kB\s+free`)
+
+	memCachedRe  = regexp.MustCompile(`(\d+)\s+kB\s+cached`)
+	memBuffersRe = regexp.MustCompile(`(\d+)\s+kB\s+buffers`)
+
+	swapTotalRe = regexp.MustCompile(`(\d+)\s+kB\s+total\s+swap`)
+	swapFreeRe  = regexp.MustCompile(`(\d+)\s+kB\s+free\s+swap`)
 )
 
 // UnmarshalJSON CPULoad of raw form "0.48 0.66 0.57 2/99 25157"
@@ -58,6 +77,16 @@ func (c *CPULoad) UnmarshalJSON(data []byte) error {
 type Memory struct {
 	Total float64
 	Free  float64
+
+	// Only reported by some firmware versions
+	Cached  *float64
+	Buffers *float64
+	Swap    *Swap
+}
+
+type Swap struct {
+	Total float64
+	Free  float64
 }
 
 // UnmarshalJSON memory of raw form "228428 kB total memory, 161732 kB free (70 %)"
@@ -87,10 +116,40 @@ func (m *Memory) UnmarshalJSON(data []byte) error {
 
 	m.Total = totalMemoryKB * 1024
 	m.Free = freeMemoryKB * 1024
+	m.Cached = parseOptionalKB(memCachedRe, rawMemoryStr)
+	m.Buffers = parseOptionalKB(memBuffersRe, rawMemoryStr)
+	m.Swap = parseSwap(rawMemoryStr)
 
 	return nil
 }
 
+// parseSwap parses swap usage of the form "524284 kB total swap, 524284 kB free swap", returns nil without swap
+func parseSwap(s string) *Swap {
+	total := parseOptionalKB(swapTotalRe, s)
+	free := parseOptionalKB(swapFreeRe, s)
+	if total == nil || free == nil {
+		return nil
+	}
+
+	return &Swap{Total: *total, Free: *free}
+}
+
+// parseOptionalKB returns the first kB value captured by re in bytes, or nil if s does not contain it
+func parseOptionalKB(re *regexp.Regexp, s string) *float64 {
+	matches := re.FindStringSubmatch(s)
+	if len(matches) < 2 {
+		return nil
+	}
+
+	kb, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return nil
+	}
+
+	bytes := kb * 1024
+	return &bytes
+}
+
 type Mount struct {
 	Size       float64 `json:"size"`
 	Used       float64 `json:"used"`
@@ -99,17 +158,17 @@ type Mount struct {
 
 func (m *Mount) UnmarshalJSON(data []byte) error {
 	aux := &struct {
-		Size       float64 `json:"size"`
-		Used       float64 `json:"used"`
-		Mountpoint string  `json:"mountpoint"`
+		Size       Number `json:"size"`
+		Used       Number `json:"used"`
+		Mountpoint string `json:"mountpoint"`
 	}{}
 
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return fmt.Errorf("failed to unmarshal mount: %v", err)
 	}
 
-	m.Size = aux.Size * 1024
-	m.Used = aux.Used * 1024
+	m.Size = float64(aux.Size) * 1024
+	m.Used = float64(aux.Used) * 1024
 	m.Mountpoint = aux.Mountpoint
 
 	return nil
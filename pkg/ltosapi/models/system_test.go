@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"testing"
+	"time"
 )
 
 func TestCPULoad_UnmarshalJSON(t *testing.T) {
@@ -73,6 +74,79 @@ func TestMemory_UnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestMemory_UnmarshalJSON_CachedBuffers(t *testing.T) {
+	t.Run("reported", func(t *testing.T) {
+		var m Memory
+		input := `"228428 kB total memory, 161732 kB free (70 %), 20480 kB buffers, 30720 kB cached"`
+		if err := json.Unmarshal([]byte(input), &m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m.Total != 228428*1024 || m.Free != 161732*1024 {
+			t.Errorf("got {%.0f, %.0f}, want {%.0f, %.0f}", m.Total, m.Free, 228428.0*1024, 161732.0*1024)
+		}
+		if m.Buffers == nil || *m.Buffers != 20480*1024 {
+			t.Errorf("Buffers = %v, want %d", m.Buffers, 20480*1024)
+		}
+		if m.Cached == nil || *m.Cached != 30720*1024 {
+			t.Errorf("Cached = %v, want %d", m.Cached, 30720*1024)
+		}
+	})
+
+	t.Run("not reported", func(t *testing.T) {
+		var m Memory
+		if err := json.Unmarshal([]byte(`"228428 kB total memory, 161732 kB free (70 %)"`), &m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m.Buffers != nil || m.Cached != nil {
+			t.Errorf("expected nil Buffers and Cached, got %v, %v", m.Buffers, m.Cached)
+		}
+	})
+}
+
+func TestParseSwap(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *Swap
+	}{
+		{"with swap", "228428 kB total memory, 161732 kB free (70 %), 524284 kB total swap, 520188 kB free swap", &Swap{524284 * 1024, 520188 * 1024}},
+		{"no swap", "228428 kB total memory, 161732 kB free (70 %)", nil},
+		{"incomplete swap", "228428 kB total memory, 161732 kB free (70 %), 524284 kB total swap", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseSwap(tt.input)
+			if (got == nil) != (tt.expected == nil) || (got != nil && *got != *tt.expected) {
+				t.Errorf("parseSwap() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSystem_CurrentTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		iso      string
+		expected time.Time
+		ok       bool
+	}{
+		{"milliseconds", "2026-02-11T22:05:01.533Z", time.Date(2026, 2, 11, 22, 5, 1, 533000000, time.UTC), true},
+		{"offset", "2026-02-11T23:05:01+01:00", time.Date(2026, 2, 11, 22, 5, 1, 0, time.UTC), true},
+		{"missing", "", time.Time{}, false},
+		{"invalid", "2026.02.11 22:05:01", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := System{CurrentTimeISO: tt.iso}.CurrentTime()
+			if ok != tt.ok || !got.Equal(tt.expected) {
+				t.Errorf("CurrentTime() = %v, %v, want %v, %v", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
 func TestMount_UnmarshalJSON(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -84,6 +158,8 @@ func TestMount_UnmarshalJSON(t *testing.T) {
 	}{
 		{"valid", `{"size":1024,"used":512,"mountpoint":"/data"}`, 1024 * 1024, 512 * 1024, "/data", false},
 		{"zeros", `{"size":0,"used":0,"mountpoint":"/"}`, 0, 0, "/", false},
+		{"numeric strings", `{"size":"1024","used":"512","mountpoint":"/data"}`, 1024 * 1024, 512 * 1024, "/data", false},
+		{"non-numeric string", `{"size":"n/a","used":512,"mountpoint":"/data"}`, 0, 0, "", true},
 		{"invalid json", `{broken}`, 0, 0, "", true},
 	}
 
@@ -0,0 +1,144 @@
+package ltosapi
+
+import (
+	"net/http"
+	"time"
+)
+
+// ClientOption configures optional behavior of a Client
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	timeout              time.Duration
+	authBasicUser        string
+	authBasicPass        string
+	bearerToken          string
+	insecureSkipVerify   bool
+	caFile               string
+	maxResponseBytes     int64
+	userAgent            string
+	proxyURL             string
+	maxRetries           int
+	maxIdleConns         *int
+	idleConnTimeout      *time.Duration
+	transport            http.RoundTripper
+	transportWrapper     func(http.RoundTripper) http.RoundTripper
+	followRedirects      bool
+	maxConcurrentFetches int
+}
+
+// WithTimeout bounds each fetch, including retries and reading the response body, in addition to any context
+// deadline
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithBasicAuth sends the given credentials via HTTP basic authentication. Credentials are only sent if both user
+// and password are non-empty.
+func WithBasicAuth(user, pass string) ClientOption {
+	return func(c *clientConfig) {
+		c.authBasicUser = user
+		c.authBasicPass = pass
+	}
+}
+
+// WithBearerToken sends the given token in an "Authorization: Bearer" header
+func WithBearerToken(token string) ClientOption {
+	return func(c *clientConfig) {
+		c.bearerToken = token
+	}
+}
+
+// WithInsecureSkipVerify disables TLS certificate verification
+func WithInsecureSkipVerify(skip bool) ClientOption {
+	return func(c *clientConfig) {
+		c.insecureSkipVerify = skip
+	}
+}
+
+// WithCAFile verifies the device certificate against the PEM encoded CA certificates in the given file instead of
+// the system roots
+func WithCAFile(path string) ClientOption {
+	return func(c *clientConfig) {
+		c.caFile = path
+	}
+}
+
+// WithMaxResponseBytes limits the size of a response body, 0 disables the limit. Defaults to DefaultMaxResponseBytes.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *clientConfig) {
+		c.maxResponseBytes = n
+	}
+}
+
+// WithUserAgent sets the User-Agent header, an empty value keeps Go's default
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *clientConfig) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithProxyURL sends requests through the given proxy instead of HTTP_PROXY, HTTPS_PROXY and NO_PROXY from the
+// environment
+func WithProxyURL(proxyURL string) ClientOption {
+	return func(c *clientConfig) {
+		c.proxyURL = proxyURL
+	}
+}
+
+// WithMaxRetries retries requests answered with status 429 or 503 and a Retry-After header, or with an empty body, up
+// to n times
+func WithMaxRetries(n int) ClientOption {
+	return func(c *clientConfig) {
+		c.maxRetries = n
+	}
+}
+
+// WithMaxIdleConns limits the number of idle keep-alive connections kept open to the device, 0 means no limit
+func WithMaxIdleConns(n int) ClientOption {
+	return func(c *clientConfig) {
+		c.maxIdleConns = &n
+	}
+}
+
+// WithIdleConnTimeout sets how long an idle keep-alive connection to the device is kept open, 0 means no limit
+func WithIdleConnTimeout(timeout time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.idleConnTimeout = &timeout
+	}
+}
+
+// WithMaxConcurrentFetches limits how many fetches to the device run at the same time, 0 means no limit. Further
+// fetches wait for a running one to finish, bounded by their context, to protect devices whose web server handles
+// one request at a time.
+func WithMaxConcurrentFetches(n int) ClientOption {
+	return func(c *clientConfig) {
+		c.maxConcurrentFetches = n
+	}
+}
+
+// WithFollowRedirects sets whether redirects are followed, which is the default. Otherwise a redirect fails the
+// request with ErrRedirect.
+func WithFollowRedirects(follow bool) ClientOption {
+	return func(c *clientConfig) {
+		c.followRedirects = follow
+	}
+}
+
+// WithTransport sends requests through the given round tripper, e.g. to add instrumentation or to stub the device
+// in tests. The options configuring the default transport (TLS, proxy and idle connections) do not apply to it.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *clientConfig) {
+		c.transport = rt
+	}
+}
+
+// WithTransportWrapper wraps the transport used for requests, e.g. with tracing or metrics middleware. Unlike
+// WithTransport the other options keep configuring the wrapped transport. A nil wrapper is ignored.
+func WithTransportWrapper(wrap func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *clientConfig) {
+		c.transportWrapper = wrap
+	}
+}
@@ -13,8 +13,8 @@
 // limitations under the License.
 
 // Mock server utility for testing the Meinberg LTOS exporter.
-// This program reads api-status-response.json and serves it on a local HTTP server.
-// Usage: go run mock-server.go [-addr localhost] [-port 8080]
+// This program serves a JSON file (e.g. from tests/testdata/) as /api/status on a local HTTP server.
+// Usage: go run mock-server.go -file testdata/m600-gps.json [-addr localhost] [-port 8080]
 
 //go:build ignore
 
@@ -44,7 +44,7 @@ func main() {
 	// Read the JSON file
 	jsonFile, err := os.Open(*file)
 	if err != nil {
-		log.Fatalf("Failed to open api-status-response.json: %v", err)
+		log.Fatalf("Failed to open %s: %v", *file, err)
 	}
 	defer jsonFile.Close()
 
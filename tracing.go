@@ -0,0 +1,33 @@
+// Copyright 2026 Raphael Seebacher
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !otel
+
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// setupTracing refuses --enable-tracing, as the OpenTelemetry dependencies are only compiled in with the otel
+// build tag to keep the default build small
+func setupTracing(_ context.Context, cfg TracingConfig, _ *slog.Logger) (func(http.RoundTripper) http.RoundTripper, func(context.Context) error, error) {
+	if cfg.Enabled {
+		return nil, nil, errors.New("tracing support is not compiled in, rebuild with -tags otel")
+	}
+	return nil, func(context.Context) error { return nil }, nil
+}
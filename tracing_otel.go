@@ -0,0 +1,108 @@
+// Copyright 2026 Raphael Seebacher
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build otel
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptrace"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/raphaelthomas/meinberg-ltos-exporter/pkg/buildinfo"
+)
+
+// setupTracing exports spans of the requests to the device via OTLP/HTTP. Each request span carries the target URL
+// and status code, with child spans for DNS resolution, connect and TLS handshake and an event for the first
+// response byte.
+func setupTracing(ctx context.Context, cfg TracingConfig, logger *slog.Logger) (func(http.RoundTripper) http.RoundTripper, func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return nil, func(context.Context) error { return nil }, nil
+	}
+
+	// Without an endpoint the exporter falls back to OTEL_EXPORTER_OTLP_ENDPOINT or http://localhost:4318
+	var opts []otlptracehttp.Option
+	if cfg.OTLPEndpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.OTLPEndpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", "meinberg_ltos_exporter"),
+		attribute.String("service.version", buildinfo.Version),
+	))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	logger.Info("Tracing of requests to the Meinberg LTOS device enabled", "otlp_endpoint", cfg.OTLPEndpoint)
+
+	wrap := func(rt http.RoundTripper) http.RoundTripper {
+		return otelhttp.NewTransport(bodySizeTransport{rt},
+			otelhttp.WithTracerProvider(tp),
+			otelhttp.WithClientTrace(func(ctx context.Context) *httptrace.ClientTrace {
+				return otelhttptrace.NewClientTrace(ctx, otelhttptrace.WithTracerProvider(tp))
+			}),
+		)
+	}
+	return wrap, tp.Shutdown, nil
+}
+
+// bodySizeTransport records the size of the response body on the span of the request once the body is read
+type bodySizeTransport struct {
+	next http.RoundTripper
+}
+
+func (t bodySizeTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &bodySizeReader{ReadCloser: resp.Body, span: trace.SpanFromContext(r.Context())}
+	return resp, nil
+}
+
+type bodySizeReader struct {
+	io.ReadCloser
+	span trace.Span
+	read int64
+}
+
+func (b *bodySizeReader) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	// The span ends when the wrapping otelhttp body reaches EOF, so the size must be set before returning it
+	if err == io.EOF {
+		b.span.SetAttributes(attribute.Int64("http.response.body.size", b.read))
+	}
+	return n, err
+}